@@ -0,0 +1,156 @@
+package gobitstream
+
+import (
+	"github.com/pkg/errors"
+)
+
+type paddingKind int
+
+const (
+	paddingAny paddingKind = iota
+	paddingZeros
+	paddingOnes
+	paddingTrailingBits
+	paddingPattern
+)
+
+// Padding describes the content of the bits used to reach an alignment boundary.
+type Padding struct {
+	kind    paddingKind
+	pattern uint64
+	width   int
+}
+
+var (
+	// PadAny does not check the padding bits when reading. Writers fill them with zeros.
+	PadAny = Padding{kind: paddingAny}
+	// PadZeros requires all the padding bits to be zero.
+	PadZeros = Padding{kind: paddingZeros}
+	// PadOnes requires all the padding bits to be one.
+	PadOnes = Padding{kind: paddingOnes}
+	// PadTrailingBits is the rbsp_trailing_bits() padding of H.264/HEVC: a one bit followed by zeros up to
+	// the boundary. It always takes at least one bit, so a whole boundary of padding is used when already aligned.
+	PadTrailingBits = Padding{kind: paddingTrailingBits}
+)
+
+// PadPattern returns a Padding that repeats the low width bits of pattern, as fields of width bits,
+// starting at the first padding bit. A last partial repetition uses the low bits of pattern.
+func PadPattern(pattern uint64, width int) Padding {
+	return Padding{kind: paddingPattern, pattern: pattern, width: width}
+}
+
+// validate checks the parameters of the Padding.
+func (pad Padding) validate() error {
+	if pad.kind == paddingPattern && (pad.width < 1 || pad.width > 64) {
+		return errors.Wrapf(InvalidWidthError, "padding pattern width must be between 1 and 64, got: %d", pad.width)
+	}
+	return nil
+}
+
+// count returns the number of padding bits needed to move offset to the next multiple of nBits.
+func (pad Padding) count(offset, nBits int) int {
+	count := (nBits - offset%nBits) % nBits
+	if count == 0 && pad.kind == paddingTrailingBits {
+		count = nBits
+	}
+	return count
+}
+
+// walk splits count padding bits in fields of at most 64 bits and calls fn with the width and value of each field.
+func (pad Padding) walk(count int, fn func(width int, val uint64) error) error {
+	fieldWidth := 64
+	if pad.kind == paddingPattern {
+		fieldWidth = pad.width
+	}
+	if pad.kind == paddingTrailingBits && count > 0 {
+		if err := fn(1, 1); err != nil {
+			return err
+		}
+		count--
+	}
+	for count > 0 {
+		width := fieldWidth
+		if width > count {
+			width = count
+		}
+		var val uint64
+		switch pad.kind {
+		case paddingOnes:
+			val = ^uint64(0)
+		case paddingPattern:
+			val = pad.pattern
+		}
+		if width < 64 {
+			val &= 1<<width - 1
+		}
+		if err := fn(width, val); err != nil {
+			return err
+		}
+		count -= width
+	}
+	return nil
+}
+
+// checkAlignment validates the alignment boundary.
+func checkAlignment(nBits int) error {
+	if nBits <= 0 {
+		return errors.Wrapf(InvalidWidthError, "alignment must be positive, got: %d", nBits)
+	}
+	return nil
+}
+
+// AlignTo skips the padding bits up to the next multiple of nBits and returns the number of bits skipped.
+// Unless pad is PadAny the padding bits are checked, and an InvalidPaddingError is returned if they do not match.
+// On error nothing is consumed.
+func (wr *Reader) AlignTo(nBits int, pad Padding) (int, error) {
+	if err := checkAlignment(nBits); err != nil {
+		return 0, err
+	}
+	if err := pad.validate(); err != nil {
+		return 0, err
+	}
+	count := pad.count(wr.offset, nBits)
+	if count > wr.Remaining() {
+		err := errors.Wrapf(OffsetOutOfRangeError, "padding: %d, offset: %d, size: %d", count, wr.offset, wr.size)
+		return 0, errors.WithStack(err)
+	}
+
+	start := wr.offset
+	err := pad.walk(count, func(width int, expected uint64) error {
+		val, err := wr.ReadNbitsUint64(width)
+		if err != nil {
+			return err
+		}
+		if pad.kind != paddingAny && val != expected {
+			return errors.Wrapf(InvalidPaddingError, "offset: %d, expected: %X, got: %X", wr.offset-width, expected, val)
+		}
+		return nil
+	})
+	if err != nil {
+		wr.offset = start
+		return 0, err
+	}
+	return count, nil
+}
+
+// AlignTo writes padding bits up to the next multiple of nBits and returns the number of bits written.
+// PadAny pads with zeros. On error the offset is restored to where the padding starts.
+func (wr *Writer) AlignTo(nBits int, pad Padding) (int, error) {
+	if err := checkAlignment(nBits); err != nil {
+		return 0, err
+	}
+	if err := pad.validate(); err != nil {
+		return 0, err
+	}
+	count := pad.count(wr.offset, nBits)
+
+	start := wr.offset
+	err := pad.walk(count, func(width int, val uint64) error {
+		return wr.writeNbits(width, val)
+	})
+	if err != nil {
+		wr.offset = start
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,111 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+	"github.com/pkg/errors"
+)
+
+func TestWriterAlignTo(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		prefix   int
+		align    int
+		pad      gobitstream.Padding
+		count    int
+		expected uint64
+	}{
+		{name: "zeros", prefix: 3, align: 8, pad: gobitstream.PadZeros, count: 5, expected: 0x07},
+		{name: "any", prefix: 3, align: 8, pad: gobitstream.PadAny, count: 5, expected: 0x07},
+		{name: "ones", prefix: 3, align: 8, pad: gobitstream.PadOnes, count: 5, expected: 0xFF},
+		{name: "ones to word", prefix: 3, align: 64, pad: gobitstream.PadOnes, count: 61, expected: 0xFFFFFFFFFFFFFFFF},
+		{name: "aligned", prefix: 8, align: 8, pad: gobitstream.PadOnes, count: 0, expected: 0xFF},
+		{name: "trailing bits", prefix: 3, align: 8, pad: gobitstream.PadTrailingBits, count: 5, expected: 0x0F},
+		{name: "trailing bits aligned", prefix: 8, align: 8, pad: gobitstream.PadTrailingBits, count: 8, expected: 0x1FF},
+		{name: "pattern", prefix: 2, align: 16, pad: gobitstream.PadPattern(0x6, 4), count: 14, expected: 0x999B},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := gobitstream.NewWriterLE(64)
+			a.Nil(w.WriteNbitsFromWord(tc.prefix, 0xFFFF))
+			count, err := w.AlignTo(tc.align, tc.pad)
+			a.Nil(err)
+			a.Equal(tc.count, count)
+			a.Equal(tc.expected, w.Uint64())
+		})
+	}
+}
+
+func TestReaderAlignTo(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name  string
+		in    uint64
+		align int
+		pad   gobitstream.Padding
+		count int
+		fails bool
+	}{
+		{name: "zeros", in: 0x07, align: 8, pad: gobitstream.PadZeros, count: 5},
+		{name: "bad zeros", in: 0x87, align: 8, pad: gobitstream.PadZeros, fails: true},
+		{name: "any", in: 0x87, align: 8, pad: gobitstream.PadAny, count: 5},
+		{name: "ones", in: 0xFF, align: 8, pad: gobitstream.PadOnes, count: 5},
+		{name: "bad ones", in: 0xEF, align: 8, pad: gobitstream.PadOnes, fails: true},
+		{name: "trailing bits", in: 0x0F, align: 8, pad: gobitstream.PadTrailingBits, count: 5},
+		{name: "bad trailing bits", in: 0x47, align: 8, pad: gobitstream.PadTrailingBits, fails: true},
+		{name: "pattern", in: 0xDB6F, align: 16, pad: gobitstream.PadPattern(0x5, 3), count: 13},
+		{name: "word", in: 0xFFFFFFFFFFFFFFFF, align: 64, pad: gobitstream.PadOnes, count: 61},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := gobitstream.NewWriterLE(128)
+			a.Nil(w.WriteNbitsFromWord(64, tc.in))
+			a.Nil(w.WriteNbitsFromWord(64, 0))
+			a.Nil(w.Flush())
+
+			rd, err := gobitstream.NewReaderLE(128, w.Bytes())
+			a.Nil(err)
+			_, err = rd.ReadNbitsUint64(3)
+			a.Nil(err)
+
+			count, err := rd.AlignTo(tc.align, tc.pad)
+			if tc.fails {
+				a.Equal(gobitstream.InvalidPaddingError, errors.Cause(err))
+				a.Equal(3, rd.Offset())
+				return
+			}
+			a.Nil(err)
+			a.Equal(tc.count, count)
+			a.Equal(3+tc.count, rd.Offset())
+		})
+	}
+}
+
+func TestAlignToErrors(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderLE(12, []byte{0x00, 0x00})
+	a.Nil(err)
+	a.Nil(rd.SkipBits(9))
+	_, err = rd.AlignTo(16, gobitstream.PadZeros)
+	a.NotNil(err)
+	a.Equal(9, rd.Offset())
+	_, err = rd.AlignTo(0, gobitstream.PadZeros)
+	a.NotNil(err)
+	_, err = rd.AlignTo(8, gobitstream.PadPattern(1, 0))
+	a.NotNil(err)
+
+	w := gobitstream.NewWriterLE(8)
+	a.Nil(w.WriteNbitsFromWord(60, 0))
+	_, err = w.AlignTo(128, gobitstream.PadOnes)
+	a.NotNil(err)
+	_, err = w.AlignTo(64, gobitstream.PadOnes)
+	a.Nil(err)
+}
@@ -0,0 +1,97 @@
+package gobitstream
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// wordsToBigInt converts the nBits wide field held in words, least significant word first, to a big.Int.
+// With signed set the field is interpreted as a two's complement value.
+func wordsToBigInt(words []uint64, nBits int, signed bool) *big.Int {
+	n := sizeInWords(nBits)
+	buf := make([]byte, 8*n)
+	for i := 0; i < n; i++ {
+		binary.BigEndian.PutUint64(buf[8*(n-1-i):], words[i])
+	}
+	val := new(big.Int).SetBytes(buf)
+	if val.BitLen() > nBits {
+		mask := new(big.Int).Lsh(big.NewInt(1), uint(nBits))
+		val.And(val, mask.Sub(mask, big.NewInt(1)))
+	}
+	if signed && val.Bit(nBits-1) == 1 {
+		val.Sub(val, new(big.Int).Lsh(big.NewInt(1), uint(nBits)))
+	}
+	return val
+}
+
+// bigIntToWords converts val to an nBits wide field, least significant word first. Negative values are converted
+// to two's complement. An error is returned if val fits neither an unsigned nor a signed nBits wide field.
+func bigIntToWords(val *big.Int, nBits int) ([]uint64, error) {
+	if nBits <= 0 {
+		return nil, errors.Wrapf(InvalidBitsSizeError, "nBits: %d", nBits)
+	}
+	if val == nil {
+		return nil, errors.Wrap(UnexpectedCondition, "nil big.Int")
+	}
+	field := val
+	if val.Sign() < 0 {
+		// -2^(nBits-1) is the smallest signed value.
+		if new(big.Int).Neg(val).Cmp(new(big.Int).Lsh(big.NewInt(1), uint(nBits-1))) > 0 {
+			return nil, errors.Wrapf(InvalidValueSizeError, "value %s does not fit in a signed %d bits field", val, nBits)
+		}
+		field = new(big.Int).Add(val, new(big.Int).Lsh(big.NewInt(1), uint(nBits)))
+	} else if val.BitLen() > nBits {
+		return nil, errors.Wrapf(InvalidValueSizeError, "value %s does not fit in an unsigned %d bits field", val, nBits)
+	}
+
+	n := sizeInWords(nBits)
+	buf := field.FillBytes(make([]byte, 8*n))
+	words := make([]uint64, n)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint64(buf[8*(n-1-i):])
+	}
+	return words, nil
+}
+
+// GetBigIntFieldFromSlice extracts a field of bits of any width from a slice of uint64 and returns it as a big.Int.
+// With signed set the field is interpreted as a two's complement value.
+func GetBigIntFieldFromSlice(inputFieldSlice []uint64, widthInBits, offsetInBits uint64, signed bool) (*big.Int, error) {
+	field, err := GetFieldFromSlice(widthInBits, offsetInBits, inputFieldSlice, nil)
+	if err != nil {
+		return nil, err
+	}
+	return wordsToBigInt(field, int(widthInBits), signed), nil
+}
+
+// SetBigIntFieldToSlice sets a field of bits of any width in a slice of uint64 from a big.Int.
+// Negative values are set as two's complement. An error is returned if inputField fits neither an unsigned
+// nor a signed field of widthInBits bits.
+func SetBigIntFieldToSlice(destinationField []uint64, inputField *big.Int, widthInBits, offsetInBits uint64) ([]uint64, error) {
+	field, err := bigIntToWords(inputField, int(widthInBits))
+	if err != nil {
+		return nil, err
+	}
+	return SetFieldToSlice(destinationField, field, widthInBits, offsetInBits)
+}
+
+// ReadBigInt reads nBits number of bits from the bit stream and returns them as a big.Int.
+// With signed set the field is interpreted as a two's complement value.
+func (wr *Reader) ReadBigInt(nBits int, signed bool) (*big.Int, error) {
+	words, err := wr.ReadNbitsWords64(nBits)
+	if err != nil {
+		return nil, err
+	}
+	return wordsToBigInt(words, nBits, signed), nil
+}
+
+// WriteBigInt writes val as a field of nBits number of bits. Negative values are written as two's complement.
+// An error is returned, and nothing is written, if val fits neither an unsigned nor a signed nBits wide field.
+func (wr *Writer) WriteBigInt(nBits int, val *big.Int) error {
+	words, err := bigIntToWords(val, nBits)
+	if err != nil {
+		return err
+	}
+	return wr.writeNbitsFromWords(nBits, words)
+}
@@ -0,0 +1,127 @@
+package gobitstream_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestGetSetBigIntFieldFromSlice(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	hash, ok := new(big.Int).SetString("0123456789ABCDEF0011223344556677", 16)
+	a.True(ok)
+
+	testCases := []struct {
+		name   string
+		val    *big.Int
+		width  uint64
+		offset uint64
+		signed bool
+	}{
+		{name: "128 bits aligned", val: hash, width: 128, offset: 0},
+		{name: "128 bits unaligned", val: hash, width: 128, offset: 13},
+		{name: "wider than value", val: hash, width: 200, offset: 7},
+		{name: "small", val: big.NewInt(5), width: 3, offset: 62},
+		{name: "negative", val: big.NewInt(-12345), width: 100, offset: 30, signed: true},
+		{name: "most negative", val: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 69)), width: 70, offset: 1, signed: true},
+		{name: "positive signed", val: hash, width: 130, offset: 0, signed: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := make([]uint64, 5)
+			for i := range dst {
+				dst[i] = 0xFFFFFFFFFFFFFFFF
+			}
+			dst, err := gobitstream.SetBigIntFieldToSlice(dst, tc.val, tc.width, tc.offset)
+			a.Nil(err)
+			actual, err := gobitstream.GetBigIntFieldFromSlice(dst, tc.width, tc.offset, tc.signed)
+			a.Nil(err)
+			a.Equal(0, tc.val.Cmp(actual), "expected: %s, actual: %s", tc.val, actual)
+
+			// The bits around the field are left untouched.
+			if tc.offset > 0 {
+				below, err := gobitstream.Get64BitsFieldFromSlice(dst, tc.offset, 0)
+				a.Nil(err)
+				a.Equal(uint64(1)<<tc.offset-1, below)
+			}
+			above, err := gobitstream.Get64BitsFieldFromSlice(dst, 1, tc.offset+tc.width)
+			a.Nil(err)
+			a.Equal(uint64(1), above)
+		})
+	}
+
+	dst, err := gobitstream.SetBigIntFieldToSlice(make([]uint64, 2), big.NewInt(-1), 128, 0)
+	a.Nil(err)
+	a.Equal([]uint64{0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}, dst)
+	unsigned, err := gobitstream.GetBigIntFieldFromSlice(dst, 128, 0, false)
+	a.Nil(err)
+	a.Equal(128, unsigned.BitLen())
+}
+
+func TestSetBigIntFieldToSliceErrors(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	dst := make([]uint64, 3)
+	_, err := gobitstream.SetBigIntFieldToSlice(dst, new(big.Int).Lsh(big.NewInt(1), 128), 128, 0)
+	a.Equal(gobitstream.InvalidValueSizeError, errors.Cause(err))
+	_, err = gobitstream.SetBigIntFieldToSlice(dst, big.NewInt(-129), 8, 0)
+	a.Equal(gobitstream.InvalidValueSizeError, errors.Cause(err))
+	_, err = gobitstream.SetBigIntFieldToSlice(dst, big.NewInt(-128), 8, 0)
+	a.Nil(err)
+	_, err = gobitstream.SetBigIntFieldToSlice(dst, nil, 8, 0)
+	a.NotNil(err)
+	_, err = gobitstream.SetBigIntFieldToSlice(dst, big.NewInt(1), 0, 0)
+	a.NotNil(err)
+	_, err = gobitstream.GetBigIntFieldFromSlice(dst, 256, 0, false)
+	a.NotNil(err)
+}
+
+func TestReadWriteBigInt(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	key := new(big.Int).Rand(r, new(big.Int).Lsh(big.NewInt(1), 256))
+	negative := new(big.Int).Neg(new(big.Int).Rand(r, new(big.Int).Lsh(big.NewInt(1), 76)))
+
+	for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+		for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+			w, err := gobitstream.NewWriterWithOrder(3+256+77+5, byteOrder, bitOrder)
+			a.Nil(err)
+			a.Nil(w.WriteNbitsFromWord(3, 0x5))
+			a.Nil(w.WriteBigInt(256, key))
+			a.Nil(w.WriteBigInt(77, negative))
+			a.Nil(w.WriteBigInt(5, big.NewInt(0x1F)))
+			a.NotNil(w.WriteBigInt(5, big.NewInt(0x20)))
+
+			rd := w.Reader()
+			v, err := rd.ReadNbitsUint64(3)
+			a.Nil(err)
+			a.Equal(uint64(0x5), v)
+			actual, err := rd.ReadBigInt(256, false)
+			a.Nil(err)
+			a.Equal(0, key.Cmp(actual))
+			actual, err = rd.ReadBigInt(77, true)
+			a.Nil(err)
+			a.Equal(0, negative.Cmp(actual))
+			actual, err = rd.ReadBigInt(5, true)
+			a.Nil(err)
+			a.Equal(int64(-1), actual.Int64())
+			_, err = rd.ReadBigInt(1, false)
+			a.NotNil(err)
+		}
+	}
+
+	// In MSB first bit order a wide field is written most significant bit first.
+	w, err := gobitstream.NewWriterWithOrder(72, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	val, ok := new(big.Int).SetString("010203040506070809", 16)
+	a.True(ok)
+	a.Nil(w.WriteBigInt(72, val))
+	a.Nil(w.Flush())
+	a.Equal([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09}, w.Bytes()[:9])
+}
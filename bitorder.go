@@ -0,0 +1,126 @@
+package gobitstream
+
+import (
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// ByteOrder selects the order of the bytes of a bit stream, as NewReaderLE/NewReaderBE and NewWriterLE/NewWriterBE do.
+type ByteOrder int
+
+const (
+	// LittleEndian reads and writes the bit stream starting at the first byte of the slice.
+	LittleEndian ByteOrder = iota
+	// BigEndian reads and writes the bit stream starting at the last byte of the slice.
+	BigEndian
+)
+
+// BitOrder selects which bit of each byte comes first in the bit stream. It is independent of the byte order.
+// It is chosen with NewReaderWithOrder, NewReaderFromWordsWithOrder, NewStreamReaderWithOrder, NewWriterWithOrder,
+// NewGrowableWriterWithOrder, NewStreamWriterWithOrder and NewWriterOn. The other constructors use LSBFirst.
+type BitOrder int
+
+const (
+	// LSBFirst starts each byte at its least significant bit, and fields are stored least significant bit first.
+	// It is the bit order of NewReaderLE, NewReaderBE, NewWriterLE and NewWriterBE.
+	LSBFirst BitOrder = iota
+	// MSBFirst starts each byte at its most significant bit, and fields are stored most significant bit first.
+	// It is the network bit order used by most network and video protocols: the first field written occupies
+	// the top bits of the first byte.
+	MSBFirst
+)
+
+// checkOrders validates the byte order and the bit order.
+func checkOrders(byteOrder ByteOrder, bitOrder BitOrder) error {
+	if byteOrder != LittleEndian && byteOrder != BigEndian {
+		return errors.Wrapf(UnexpectedCondition, "invalid byte order: %d", byteOrder)
+	}
+	if bitOrder != LSBFirst && bitOrder != MSBFirst {
+		return errors.Wrapf(UnexpectedCondition, "invalid bit order: %d", bitOrder)
+	}
+	return nil
+}
+
+// reverseBitsInBytes reverses the order of the bits of every byte of s in place.
+func reverseBitsInBytes(s []byte) {
+	for i, b := range s {
+		s[i] = bits.Reverse8(b)
+	}
+}
+
+// reverseField reverses the order of the nBits low bits of val.
+// Callers must keep nBits between 1 and 64, the shift below is negative otherwise.
+func reverseField(val uint64, nBits int) uint64 {
+	return bits.Reverse64(val) >> (64 - nBits)
+}
+
+// reverseFieldWords reverses, in place, the order of the nBits bits of the field stored in words,
+// least significant word first. words must be exactly sizeInWords(nBits) long.
+func reverseFieldWords(words []uint64, nBits int) {
+	reverseSlice(words)
+	for i, word := range words {
+		words[i] = bits.Reverse64(word)
+	}
+	shift := len(words)*64 - nBits
+	if shift == 0 {
+		return
+	}
+	for i := range words {
+		words[i] >>= shift
+		if i+1 < len(words) {
+			words[i] |= words[i+1] << (64 - shift)
+		}
+	}
+}
+
+// NewReaderWithOrder creates a new Reader with the specified size in bits, input byte slice, byte order and bit order.
+// With MSBFirst, ReadNbitsUint64 and ReadNbitsWords64 return the first bit read as the most significant bit of the
+// value, and ReadNbitsBytes returns the bits with the first one in the most significant bit of the first byte.
+// It returns an error if the input byte slice is smaller than the specified size in bits.
+func NewReaderWithOrder(sizeInBits int, in []byte, byteOrder ByteOrder, bitOrder BitOrder) (wr *Reader, err error) {
+	if err = checkOrders(byteOrder, bitOrder); err != nil {
+		return nil, err
+	}
+	if bitOrder == MSBFirst {
+		inx := make([]byte, len(in))
+		_ = copy(inx, in)
+		reverseBitsInBytes(inx)
+		in = inx
+	}
+	if byteOrder == BigEndian {
+		wr, err = NewReaderBE(sizeInBits, in)
+	} else {
+		wr, err = NewReaderLE(sizeInBits, in)
+	}
+	if err != nil {
+		return nil, err
+	}
+	wr.bitOrder = bitOrder
+	return wr, nil
+}
+
+// NewWriterWithOrder creates a new Writer with the specified size in bits, byte order and bit order.
+// With MSBFirst, values are written most significant bit first, and the first field written occupies the top bits
+// of the first byte.
+func NewWriterWithOrder(totalBits int, byteOrder ByteOrder, bitOrder BitOrder) (*Writer, error) {
+	if err := checkOrders(byteOrder, bitOrder); err != nil {
+		return nil, err
+	}
+	wr := newWriter(totalBits)
+	wr.isLittleEndian = byteOrder == LittleEndian
+	wr.bitOrder = bitOrder
+	return wr, nil
+}
+
+// NewGrowableWriterWithOrder creates a new growable Writer with the specified byte order and bit order,
+// as NewGrowableWriterLE and NewGrowableWriterBE do for the LSBFirst bit order.
+func NewGrowableWriterWithOrder(byteOrder ByteOrder, bitOrder BitOrder) (*Writer, error) {
+	if err := checkOrders(byteOrder, bitOrder); err != nil {
+		return nil, err
+	}
+	wr := NewGrowableWriterLE()
+	wr.isLittleEndian = byteOrder == LittleEndian
+	wr.bitOrder = bitOrder
+	return wr, nil
+}
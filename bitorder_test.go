@@ -0,0 +1,251 @@
+package gobitstream_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+// msbFirstBits returns the bits of in in network bit order.
+func msbFirstBits(in []byte) []uint64 {
+	res := make([]uint64, 0, len(in)*8)
+	for _, b := range in {
+		for i := 7; i >= 0; i-- {
+			res = append(res, uint64(b>>i)&1)
+		}
+	}
+	return res
+}
+
+func TestReaderMSBFirst(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	in := make([]byte, 100)
+	r.Read(in)
+	stream := msbFirstBits(in)
+
+	rd, err := gobitstream.NewReaderWithOrder(len(in)*8, in, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+
+	for offset := 0; offset < len(stream); {
+		width := r.Intn(64) + 1
+		if width > len(stream)-offset {
+			width = len(stream) - offset
+		}
+		var expected uint64
+		for _, bit := range stream[offset : offset+width] {
+			expected = expected<<1 | bit
+		}
+		v, err := rd.ReadNbitsUint64(width)
+		a.Nil(err)
+		a.Equal(expected, v, "offset: %d, width: %d", offset, width)
+		offset += width
+	}
+}
+
+func TestReaderMSBFirstWideFields(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	in := []byte{0x80, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0xF0}
+	rd, err := gobitstream.NewReaderWithOrder(80, in, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+
+	words, err := rd.ReadNbitsWords64(68)
+	a.Nil(err)
+	a.Equal([]uint64{0x0010203040506070, 0x8}, words)
+
+	rd.Reset()
+	v, err := rd.ReadNbitsUint64(4)
+	a.Nil(err)
+	a.Equal(uint64(0x8), v)
+	out, err := rd.ReadNbitsBytes(12)
+	a.Nil(err)
+	a.Equal([]byte{0x00, 0x10}, out)
+}
+
+func TestReaderMSBFirstRejectsWideUint64(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+		rd, err := gobitstream.NewReaderWithOrder(128, make([]byte, 16), gobitstream.LittleEndian, bitOrder)
+		a.Nil(err)
+		_, err = rd.PeekNbitsUint64(100)
+		a.Equal(gobitstream.InvalidBitsSizeError, errors.Cause(err))
+		_, err = rd.ReadNbitsUint64(65)
+		a.Equal(gobitstream.InvalidBitsSizeError, errors.Cause(err))
+		a.Equal(128, rd.Remaining())
+		v, err := rd.ReadNbitsUint64(64)
+		a.Nil(err)
+		a.Equal(uint64(0), v)
+	}
+}
+
+func TestWriterMSBFirst(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w, err := gobitstream.NewWriterWithOrder(32, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	a.Nil(w.WriteNbitsFromWord(3, 0x5))
+	a.Nil(w.WriteNbitsFromWord(5, 0x3))
+	a.Nil(w.WriteNbitsFromWord(12, 0xABC))
+	a.Nil(w.WriteNbitsFromBytes(4, []byte{0xD0}))
+	a.Nil(w.Flush())
+	a.Equal([]byte{0xA3, 0xAB, 0xCD}, w.Bytes())
+
+	rd := w.Reader()
+	for _, tc := range []struct {
+		width    int
+		expected uint64
+	}{{3, 0x5}, {5, 0x3}, {12, 0xABC}, {4, 0xD}} {
+		v, err := rd.ReadNbitsUint64(tc.width)
+		a.Nil(err)
+		a.Equal(tc.expected, v)
+	}
+}
+
+func TestMSBFirstExpGolomb(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	// ue(v) values 0, 1, 2 and 3 in network order: 1 010 011 00100.
+	w, err := gobitstream.NewWriterWithOrder(16, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	for v := uint64(0); v < 4; v++ {
+		a.Nil(w.WriteUE(v))
+	}
+	a.Nil(w.Flush())
+	a.Equal([]byte{0xA6, 0x40}, w.Bytes())
+
+	rd, err := gobitstream.NewReaderWithOrder(16, w.Bytes(), gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	for expected := uint64(0); expected < 4; expected++ {
+		v, err := rd.ReadUE()
+		a.Nil(err)
+		a.Equal(expected, v)
+	}
+}
+
+func TestBitOrderRoundTrip(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+		for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+			const totalBits = 2000
+			w, err := gobitstream.NewWriterWithOrder(totalBits, byteOrder, bitOrder)
+			a.Nil(err)
+
+			var widths []int
+			var values []uint64
+			for offset := 0; offset < totalBits; {
+				width := r.Intn(64) + 1
+				if width > totalBits-offset {
+					width = totalBits - offset
+				}
+				widths = append(widths, width)
+				values = append(values, r.Uint64()&(1<<width-1))
+				a.Nil(w.WriteNbitsFromWord(width, values[len(values)-1]))
+				offset += width
+			}
+			a.Nil(w.Flush())
+
+			rd, err := gobitstream.NewReaderWithOrder(totalBits, w.Bytes(), byteOrder, bitOrder)
+			a.Nil(err)
+			for i, width := range widths {
+				v, err := rd.ReadNbitsUint64(width)
+				a.Nil(err)
+				a.Equal(values[i], v, "byte order: %d, bit order: %d", byteOrder, bitOrder)
+			}
+		}
+	}
+
+	_, err := gobitstream.NewWriterWithOrder(8, gobitstream.LittleEndian, gobitstream.BitOrder(7))
+	a.NotNil(err)
+	_, err = gobitstream.NewReaderWithOrder(8, []byte{0}, gobitstream.ByteOrder(7), gobitstream.MSBFirst)
+	a.NotNil(err)
+}
+
+func TestBitOrderOtherConstructors(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	payload := []byte{0xC3, 0x5A, 0x0F}
+	for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+		const totalBits = 1000
+		var widths []int
+		var values []uint64
+		for offset := 0; offset < totalBits-20; {
+			width := r.Intn(64) + 1
+			if width > totalBits-20-offset {
+				width = totalBits - 20 - offset
+			}
+			widths = append(widths, width)
+			values = append(values, r.Uint64()&(1<<width-1))
+			offset += width
+		}
+		write := func(w interface {
+			WriteNbitsFromWord(int, uint64) error
+			WriteNbitsFromBytes(int, []byte) error
+		}) {
+			for i, width := range widths {
+				a.Nil(w.WriteNbitsFromWord(width, values[i]))
+			}
+			a.Nil(w.WriteNbitsFromBytes(20, payload))
+		}
+
+		fixed, err := gobitstream.NewWriterWithOrder(totalBits, gobitstream.LittleEndian, bitOrder)
+		a.Nil(err)
+		write(fixed)
+		a.Nil(fixed.Flush())
+
+		for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+			expected, err := gobitstream.NewWriterWithOrder(totalBits, byteOrder, bitOrder)
+			a.Nil(err)
+			write(expected)
+			a.Nil(expected.Flush())
+			growable, err := gobitstream.NewGrowableWriterWithOrder(byteOrder, bitOrder)
+			a.Nil(err)
+			write(growable)
+			a.Nil(growable.Flush())
+			a.Equal(expected.Bytes(), growable.Bytes())
+		}
+
+		var out bytes.Buffer
+		sw, err := gobitstream.NewStreamWriterWithOrder(&out, bitOrder)
+		a.Nil(err)
+		write(sw)
+		a.Nil(sw.Close())
+		a.Equal(fixed.Bytes(), out.Bytes())
+
+		sr, err := gobitstream.NewStreamReaderWithOrder(bytes.NewReader(out.Bytes()), bitOrder, gobitstream.WithBufferSize(16))
+		a.Nil(err)
+		wr, err := gobitstream.NewReaderFromWordsWithOrder(totalBits, fixed.Words(), bitOrder)
+		a.Nil(err)
+		for i, width := range widths {
+			v, err := sr.ReadNbitsUint64(width)
+			a.Nil(err)
+			a.Equal(values[i], v, "bit order: %d", bitOrder)
+			v, err = wr.ReadNbitsUint64(width)
+			a.Nil(err)
+			a.Equal(values[i], v, "bit order: %d", bitOrder)
+		}
+		rd, err := gobitstream.NewReaderWithOrder(totalBits, fixed.Bytes(), gobitstream.LittleEndian, bitOrder)
+		a.Nil(err)
+		a.Nil(rd.SkipBits(totalBits - 20))
+		expected, err := rd.ReadNbitsBytes(20)
+		a.Nil(err)
+		got, err := sr.ReadNbitsBytes(20)
+		a.Nil(err)
+		a.Equal(expected, got)
+	}
+
+	_, err := gobitstream.NewGrowableWriterWithOrder(gobitstream.LittleEndian, gobitstream.BitOrder(5))
+	a.NotNil(err)
+	_, err = gobitstream.NewStreamWriterWithOrder(&bytes.Buffer{}, gobitstream.BitOrder(5))
+	a.NotNil(err)
+	_, err = gobitstream.NewStreamReaderWithOrder(&bytes.Buffer{}, gobitstream.BitOrder(5))
+	a.NotNil(err)
+	_, err = gobitstream.NewReaderFromWordsWithOrder(0, nil, gobitstream.BitOrder(5))
+	a.NotNil(err)
+}
@@ -0,0 +1,290 @@
+package gobitstream
+
+import (
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// MaxBCDDigits is the largest number of packed BCD digits that fit in a uint64.
+const MaxBCDDigits = 16
+
+func checkBCDDigits(nDigits int) error {
+	if nDigits <= 0 || nDigits > MaxBCDDigits {
+		return errors.Wrapf(InvalidWidthError, "BCD digits must be between 1 and %d, got: %d", MaxBCDDigits, nDigits)
+	}
+	return nil
+}
+
+// checkCodeWidth checks the width of a field decoded from a single word.
+func checkCodeWidth(nBits int) error {
+	if nBits <= 0 || nBits > 64 {
+		return errors.Wrapf(InvalidBitsSizeError, "nBits: %d", nBits)
+	}
+	return nil
+}
+
+// BCDToBinary converts nDigits packed BCD digits, least significant digit in the low nibble, to their binary value.
+// An InvalidBCDDigitError is returned if a nibble is greater than 9.
+func BCDToBinary(bcd uint64, nDigits int) (uint64, error) {
+	if err := checkBCDDigits(nDigits); err != nil {
+		return 0, err
+	}
+	var val uint64
+	for i := nDigits - 1; i >= 0; i-- {
+		digit := (bcd >> (4 * i)) & 0xF
+		if digit > 9 {
+			return 0, errors.Wrapf(InvalidBCDDigitError, "digit %d is %X in %X", i, digit, bcd)
+		}
+		val = val*10 + digit
+	}
+	return val, nil
+}
+
+// BinaryToBCD converts val to nDigits packed BCD digits, least significant digit in the low nibble.
+// An error is returned if val has more than nDigits decimal digits.
+func BinaryToBCD(val uint64, nDigits int) (uint64, error) {
+	if err := checkBCDDigits(nDigits); err != nil {
+		return 0, err
+	}
+	var bcd uint64
+	rest := val
+	for i := 0; i < nDigits; i++ {
+		bcd |= (rest % 10) << (4 * i)
+		rest /= 10
+	}
+	if rest != 0 {
+		return 0, errors.Wrapf(InvalidValueSizeError, "value %d does not fit in %d BCD digits", val, nDigits)
+	}
+	return bcd, nil
+}
+
+// BinaryToGray converts val to its binary-reflected Gray code.
+func BinaryToGray(val uint64) uint64 {
+	return val ^ val>>1
+}
+
+// GrayToBinary converts a binary-reflected Gray code to its binary value.
+func GrayToBinary(gray uint64) uint64 {
+	for shift := 1; shift < 64; shift <<= 1 {
+		gray ^= gray >> shift
+	}
+	return gray
+}
+
+// BinaryToGrayWords converts the nBits wide value held in words, least significant word first,
+// to its binary-reflected Gray code. The result is a new slice of sizeInWords(nBits) words.
+func BinaryToGrayWords(words []uint64, nBits int) ([]uint64, error) {
+	n, err := checkWordsField(words, nBits)
+	if err != nil {
+		return nil, err
+	}
+	gray := make([]uint64, n)
+	_ = copy(gray, words[:n])
+	maskTopWord(gray, nBits)
+	for i := range gray {
+		next := gray[i] >> 1
+		if i+1 < n {
+			next |= gray[i+1] << 63
+		}
+		gray[i] ^= next
+	}
+	return gray, nil
+}
+
+// GrayToBinaryWords converts the nBits wide binary-reflected Gray code held in words, least significant word first,
+// to its binary value. The result is a new slice of sizeInWords(nBits) words.
+func GrayToBinaryWords(words []uint64, nBits int) ([]uint64, error) {
+	n, err := checkWordsField(words, nBits)
+	if err != nil {
+		return nil, err
+	}
+	val := make([]uint64, n)
+	_ = copy(val, words[:n])
+	maskTopWord(val, nBits)
+	// Every binary bit is the parity of the Gray bits above it, so the lowest binary bit of a word
+	// carries into all the bits of the word below.
+	var carry uint64
+	for i := n - 1; i >= 0; i-- {
+		val[i] = GrayToBinary(val[i])
+		if carry != 0 {
+			val[i] = ^val[i]
+		}
+		carry = val[i] & 1
+	}
+	return val, nil
+}
+
+func checkWordsField(words []uint64, nBits int) (int, error) {
+	n := sizeInWords(nBits)
+	if nBits <= 0 || len(words) < n {
+		return 0, errors.Wrapf(InvalidBitsSizeError, "nBits: %d, words: %d", nBits, len(words))
+	}
+	return n, nil
+}
+
+// maskTopWord clears the bits of words past nBits.
+func maskTopWord(words []uint64, nBits int) {
+	if rem := nBits % 64; rem != 0 {
+		words[len(words)-1] &= ^uint64(0) >> (64 - rem)
+	}
+}
+
+// OneHotToIndex returns the index of the single bit set in val.
+// An InvalidOneHotError is returned if val does not have exactly one bit set.
+func OneHotToIndex(val uint64) (int, error) {
+	if bits.OnesCount64(val) != 1 {
+		return 0, errors.Wrapf(InvalidOneHotError, "%X has %d bits set", val, bits.OnesCount64(val))
+	}
+	return bits.TrailingZeros64(val), nil
+}
+
+// IndexToOneHot returns the nBits wide one-hot value with bit index set. index must be lower than nBits.
+func IndexToOneHot(index, nBits int) (uint64, error) {
+	if err := checkCodeWidth(nBits); err != nil {
+		return 0, err
+	}
+	if index < 0 || index >= nBits {
+		return 0, errors.Wrapf(InvalidValueSizeError, "index %d does not fit in a %d bits one-hot field", index, nBits)
+	}
+	return 1 << index, nil
+}
+
+// ThermometerToCount returns the number of bits set in a thermometer code, where count bits are set starting from
+// the least significant bit. An InvalidThermometerError is returned if the bits set are not contiguous from bit 0.
+func ThermometerToCount(val uint64) (int, error) {
+	if val&(val+1) != 0 {
+		return 0, errors.Wrapf(InvalidThermometerError, "%X is not a thermometer code", val)
+	}
+	return bits.OnesCount64(val), nil
+}
+
+// CountToThermometer returns the nBits wide thermometer code with the count low bits set. count must not exceed nBits.
+func CountToThermometer(count, nBits int) (uint64, error) {
+	if err := checkCodeWidth(nBits); err != nil {
+		return 0, err
+	}
+	if count < 0 || count > nBits {
+		return 0, errors.Wrapf(InvalidValueSizeError, "count %d does not fit in a %d bits thermometer field", count, nBits)
+	}
+	if count == 64 {
+		return ^uint64(0), nil
+	}
+	return 1<<count - 1, nil
+}
+
+// ReadBCD reads nDigits packed BCD digits, 4*nDigits bits, and returns their binary value.
+// If a digit is invalid an InvalidBCDDigitError is returned and nothing is consumed.
+func (wr *Reader) ReadBCD(nDigits int) (uint64, error) {
+	if err := checkBCDDigits(nDigits); err != nil {
+		return 0, err
+	}
+	return wr.readDecoded(4*nDigits, func(field uint64) (uint64, error) {
+		return BCDToBinary(field, nDigits)
+	})
+}
+
+// ReadGray reads an nBits wide binary-reflected Gray code and returns its binary value.
+// nBits must be between 1 and 64, use ReadGrayWords for wider fields.
+func (wr *Reader) ReadGray(nBits int) (uint64, error) {
+	if err := checkCodeWidth(nBits); err != nil {
+		return 0, err
+	}
+	field, err := wr.ReadNbitsUint64(nBits)
+	if err != nil {
+		return 0, err
+	}
+	return GrayToBinary(field), nil
+}
+
+// ReadGrayWords reads an nBits wide binary-reflected Gray code of any width and returns its binary value,
+// least significant word first.
+func (wr *Reader) ReadGrayWords(nBits int) ([]uint64, error) {
+	field, err := wr.ReadNbitsWords64(nBits)
+	if err != nil {
+		return nil, err
+	}
+	return GrayToBinaryWords(field, nBits)
+}
+
+// ReadOneHot reads an nBits wide one-hot field, nBits between 1 and 64, and returns the index of the bit set.
+// If the field does not have exactly one bit set an InvalidOneHotError is returned and nothing is consumed.
+func (wr *Reader) ReadOneHot(nBits int) (int, error) {
+	index, err := wr.readDecoded(nBits, func(field uint64) (uint64, error) {
+		index, err := OneHotToIndex(field)
+		return uint64(index), err
+	})
+	return int(index), err
+}
+
+// ReadThermometer reads an nBits wide thermometer field, nBits between 1 and 64, and returns the number of bits set.
+// If the bits set are not contiguous from bit 0 an InvalidThermometerError is returned and nothing is consumed.
+func (wr *Reader) ReadThermometer(nBits int) (int, error) {
+	count, err := wr.readDecoded(nBits, func(field uint64) (uint64, error) {
+		count, err := ThermometerToCount(field)
+		return uint64(count), err
+	})
+	return int(count), err
+}
+
+// readDecoded reads an nBits wide field, up to 64 bits, and decodes it, consuming the field only if decode succeeds.
+func (wr *Reader) readDecoded(nBits int, decode func(uint64) (uint64, error)) (uint64, error) {
+	if err := checkCodeWidth(nBits); err != nil {
+		return 0, err
+	}
+	field, err := wr.PeekNbitsUint64(nBits)
+	if err != nil {
+		return 0, err
+	}
+	val, err := decode(field)
+	if err != nil {
+		return 0, errors.Wrapf(err, "offset: %d", wr.offset)
+	}
+	wr.offset += nBits
+	return val, nil
+}
+
+// WriteBCD writes val as nDigits packed BCD digits, 4*nDigits bits.
+func (wr *Writer) WriteBCD(nDigits int, val uint64) error {
+	bcd, err := BinaryToBCD(val, nDigits)
+	if err != nil {
+		return err
+	}
+	return wr.WriteNbitsFromWord(4*nDigits, bcd)
+}
+
+// WriteGray writes val as an nBits wide binary-reflected Gray code.
+func (wr *Writer) WriteGray(nBits int, val uint64) error {
+	return wr.WriteNbitsFromWord(nBits, BinaryToGray(val))
+}
+
+// WriteGrayWords writes the nBits wide value held in words, least significant word first,
+// as a binary-reflected Gray code of any width.
+func (wr *Writer) WriteGrayWords(nBits int, words []uint64) error {
+	if err := wr.checkStrictWords(nBits, words); err != nil {
+		return err
+	}
+	gray, err := BinaryToGrayWords(words, nBits)
+	if err != nil {
+		return err
+	}
+	return wr.writeNbitsFromWords(nBits, gray)
+}
+
+// WriteOneHot writes an nBits wide one-hot field with bit index set.
+func (wr *Writer) WriteOneHot(nBits, index int) error {
+	val, err := IndexToOneHot(index, nBits)
+	if err != nil {
+		return err
+	}
+	return wr.WriteNbitsFromWord(nBits, val)
+}
+
+// WriteThermometer writes an nBits wide thermometer field with the count low bits set.
+func (wr *Writer) WriteThermometer(nBits, count int) error {
+	val, err := CountToThermometer(count, nBits)
+	if err != nil {
+		return err
+	}
+	return wr.WriteNbitsFromWord(nBits, val)
+}
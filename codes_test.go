@@ -0,0 +1,252 @@
+package gobitstream_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestBCD(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name    string
+		bcd     uint64
+		nDigits int
+		val     uint64
+	}{
+		{name: "zero", bcd: 0x0, nDigits: 1, val: 0},
+		{name: "seconds", bcd: 0x59, nDigits: 2, val: 59},
+		{name: "year", bcd: 0x2024, nDigits: 4, val: 2024},
+		{name: "leading zeros", bcd: 0x0007, nDigits: 4, val: 7},
+		{name: "max digits", bcd: 0x9999999999999999, nDigits: 16, val: 9999999999999999},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := gobitstream.BCDToBinary(tc.bcd, tc.nDigits)
+			a.Nil(err)
+			a.Equal(tc.val, val)
+			bcd, err := gobitstream.BinaryToBCD(tc.val, tc.nDigits)
+			a.Nil(err)
+			a.Equal(tc.bcd, bcd)
+		})
+	}
+
+	_, err := gobitstream.BCDToBinary(0x1A, 2)
+	a.Equal(gobitstream.InvalidBCDDigitError, errors.Cause(err))
+	_, err = gobitstream.BCDToBinary(0xF0, 2)
+	a.Equal(gobitstream.InvalidBCDDigitError, errors.Cause(err))
+	_, err = gobitstream.BinaryToBCD(100, 2)
+	a.NotNil(err)
+	_, err = gobitstream.BinaryToBCD(1, 17)
+	a.NotNil(err)
+	_, err = gobitstream.BCDToBinary(1, 0)
+	a.NotNil(err)
+}
+
+func TestGray(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	expected := []uint64{0, 1, 3, 2, 6, 7, 5, 4}
+	for i, gray := range expected {
+		a.Equal(gray, gobitstream.BinaryToGray(uint64(i)))
+		a.Equal(uint64(i), gobitstream.GrayToBinary(gray))
+	}
+
+	for i := 0; i < 1000; i++ {
+		val := r.Uint64()
+		gray := gobitstream.BinaryToGray(val)
+		a.Equal(val, gobitstream.GrayToBinary(gray))
+		// Consecutive values differ by a single bit.
+		next := gobitstream.BinaryToGray(val + 1)
+		a.Equal(uint64(1), uint64(countBits(gray^next)))
+	}
+}
+
+func TestGrayWords(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	for _, nBits := range []int{1, 63, 64, 65, 128, 200} {
+		for i := 0; i < 100; i++ {
+			words := make([]uint64, (nBits+63)/64)
+			for j := range words {
+				words[j] = r.Uint64()
+			}
+			if nBits%64 != 0 {
+				words[len(words)-1] >>= 64 - nBits%64
+			}
+
+			gray, err := gobitstream.BinaryToGrayWords(words, nBits)
+			a.Nil(err)
+			val := wordsToBig(words)
+			a.Equal(0, new(big.Int).Xor(val, new(big.Int).Rsh(val, 1)).Cmp(wordsToBig(gray)))
+			if nBits <= 64 {
+				a.Equal(gobitstream.BinaryToGray(words[0]), gray[0])
+			}
+
+			back, err := gobitstream.GrayToBinaryWords(gray, nBits)
+			a.Nil(err)
+			a.Equal(words, back)
+		}
+	}
+
+	_, err := gobitstream.BinaryToGrayWords([]uint64{0}, 65)
+	a.NotNil(err)
+	_, err = gobitstream.GrayToBinaryWords([]uint64{0}, 0)
+	a.NotNil(err)
+}
+
+func TestOneHotAndThermometer(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	for i := 0; i < 64; i++ {
+		oneHot, err := gobitstream.IndexToOneHot(i, 64)
+		a.Nil(err)
+		index, err := gobitstream.OneHotToIndex(oneHot)
+		a.Nil(err)
+		a.Equal(i, index)
+	}
+	for i := 0; i <= 64; i++ {
+		thermometer, err := gobitstream.CountToThermometer(i, 64)
+		a.Nil(err)
+		count, err := gobitstream.ThermometerToCount(thermometer)
+		a.Nil(err)
+		a.Equal(i, count)
+	}
+
+	for _, val := range []uint64{0, 0x3, 0x8001} {
+		_, err := gobitstream.OneHotToIndex(val)
+		a.Equal(gobitstream.InvalidOneHotError, errors.Cause(err))
+	}
+	for _, val := range []uint64{0x2, 0x5, 0xE, 0x8000000000000000} {
+		_, err := gobitstream.ThermometerToCount(val)
+		a.Equal(gobitstream.InvalidThermometerError, errors.Cause(err))
+	}
+	_, err := gobitstream.IndexToOneHot(8, 8)
+	a.NotNil(err)
+	_, err = gobitstream.IndexToOneHot(0, 65)
+	a.NotNil(err)
+	_, err = gobitstream.CountToThermometer(9, 8)
+	a.NotNil(err)
+}
+
+func TestReadWriteCodes(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	grayWords := []uint64{0x0123456789ABCDEF, 0x1F}
+	for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+		w, err := gobitstream.NewWriterWithOrder(3+24+13+69+8+8+5, gobitstream.LittleEndian, bitOrder)
+		a.Nil(err)
+		a.Nil(w.WriteNbitsFromWord(3, 0x1))
+		a.Nil(w.WriteBCD(6, 235959))
+		a.Nil(w.WriteGray(13, 4095))
+		a.Nil(w.WriteGrayWords(69, grayWords))
+		a.Nil(w.WriteOneHot(8, 5))
+		a.Nil(w.WriteThermometer(8, 3))
+		a.Nil(w.WriteNbitsFromWord(5, 0x1F))
+		a.NotNil(w.WriteBCD(2, 100))
+		a.NotNil(w.WriteOneHot(8, 8))
+		a.NotNil(w.WriteThermometer(8, 9))
+
+		rd := w.Reader()
+		_, err = rd.ReadNbitsUint64(3)
+		a.Nil(err)
+		val, err := rd.ReadBCD(6)
+		a.Nil(err)
+		a.Equal(uint64(235959), val)
+		val, err = rd.ReadGray(13)
+		a.Nil(err)
+		a.Equal(uint64(4095), val)
+		words, err := rd.ReadGrayWords(69)
+		a.Nil(err)
+		a.Equal(grayWords, words)
+		index, err := rd.ReadOneHot(8)
+		a.Nil(err)
+		a.Equal(5, index)
+		count, err := rd.ReadThermometer(8)
+		a.Nil(err)
+		a.Equal(3, count)
+
+		// The trailing 0x1F is not a valid BCD digit, one-hot or thermometer field.
+		offset := rd.Offset()
+		_, err = rd.ReadBCD(1)
+		a.Equal(gobitstream.InvalidBCDDigitError, errors.Cause(err))
+		_, err = rd.ReadOneHot(5)
+		a.Equal(gobitstream.InvalidOneHotError, errors.Cause(err))
+		_, err = rd.ReadThermometer(2)
+		a.Nil(err)
+		a.Equal(offset+2, rd.Offset())
+	}
+}
+
+func TestReadCodesRejectMalformed(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderLE(8, []byte{0x06})
+	a.Nil(err)
+	_, err = rd.ReadThermometer(8)
+	a.Equal(gobitstream.InvalidThermometerError, errors.Cause(err))
+	_, err = rd.ReadOneHot(8)
+	a.Equal(gobitstream.InvalidOneHotError, errors.Cause(err))
+	a.Equal(0, rd.Offset())
+	val, err := rd.ReadBCD(2)
+	a.Nil(err)
+	a.Equal(uint64(6), val)
+}
+
+func countBits(val uint64) int {
+	count := 0
+	for ; val != 0; val &= val - 1 {
+		count++
+	}
+	return count
+}
+
+func wordsToBig(words []uint64) *big.Int {
+	val := new(big.Int)
+	for i := len(words) - 1; i >= 0; i-- {
+		val.Lsh(val, 64)
+		val.Or(val, new(big.Int).SetUint64(words[i]))
+	}
+	return val
+}
+
+func TestReadCodesRejectWideFields(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	// A 100 bits field with bits 3 and 80 set is not one-hot, and must not be decoded from its low word.
+	in := make([]byte, 16)
+	in[0] = 0x08
+	in[10] = 0x01
+	for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+		rd, err := gobitstream.NewReaderWithOrder(128, in, gobitstream.LittleEndian, bitOrder)
+		a.Nil(err)
+
+		testCases := []struct {
+			name string
+			read func(nBits int) error
+		}{
+			{name: "one-hot", read: func(nBits int) error { _, err := rd.ReadOneHot(nBits); return err }},
+			{name: "thermometer", read: func(nBits int) error { _, err := rd.ReadThermometer(nBits); return err }},
+			{name: "gray", read: func(nBits int) error { _, err := rd.ReadGray(nBits); return err }},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				a.Equal(gobitstream.InvalidBitsSizeError, errors.Cause(tc.read(100)))
+				a.Equal(gobitstream.InvalidBitsSizeError, errors.Cause(tc.read(65)))
+				a.Equal(gobitstream.InvalidBitsSizeError, errors.Cause(tc.read(0)))
+				a.Equal(0, rd.Offset())
+			})
+		}
+
+		words, err := rd.ReadGrayWords(100)
+		a.Nil(err)
+		a.Equal(2, len(words))
+	}
+}
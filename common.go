@@ -92,6 +92,25 @@ func ConvertBytesToWords(nBits int, val []byte) (words []uint64, err error) {
 	return words, nil
 }
 
+// packBytesToWords packs the bytes holding nBits bits into words, like ConvertBytesToWords but without allocating.
+// words must be bitsToWordSize(nBits) long and val at least BitsToBytesSize(nBits) long.
+func packBytesToWords(words []uint64, nBits int, val []byte) {
+	byteSize := BitsToBytesSize(nBits)
+	for i := range words {
+		chunk := val[i*8 : byteSize]
+		if len(chunk) >= 8 {
+			words[i] = binary.LittleEndian.Uint64(chunk)
+			continue
+		}
+		var word uint64
+		for j := len(chunk) - 1; j >= 0; j-- {
+			word = word<<8 | uint64(chunk[j])
+		}
+		words[i] = word
+	}
+	maskTopWord(words, nBits)
+}
+
 // BitsToBytesSize calculates the number of bytes required to accommodate a certain number of bits.
 // If the number of bits is not an exact multiple of 8 (since there are 8 bits in a byte),
 // it adds one more to the byte count to accommodate the extra bits.
@@ -0,0 +1,127 @@
+package gobitstream
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// CoverageMode selects whether a Writer tracks which bits of the stream are written, to detect holes and
+// double writes in frames built with WriteBitsAt, Reserve or manual offset juggling.
+type CoverageMode int
+
+const (
+	// CoverageOff does not track the bits written. It is the default.
+	CoverageOff CoverageMode = iota
+	// CoverageReport tracks the bits written, and Coverage reports the gaps and the overlaps.
+	CoverageReport
+	// CoverageFail tracks the bits written like CoverageReport, and Flush returns a *CoverageError
+	// if there are gaps or overlaps.
+	CoverageFail
+)
+
+// BitRange is a range of Width bits starting at bit Offset of the stream.
+type BitRange struct {
+	Offset int
+	Width  int
+}
+
+func (r BitRange) String() string {
+	return fmt.Sprintf("[%d, %d)", r.Offset, r.Offset+r.Width)
+}
+
+// SetCoverage selects the coverage tracking mode of the Writer. The bits written so far are counted as written once.
+// Tracking keeps two bitmaps using the layout of the backing words, so it costs two bits of memory per bit written.
+func (wr *Writer) SetCoverage(mode CoverageMode) error {
+	if mode != CoverageOff && mode != CoverageReport && mode != CoverageFail {
+		return errors.Wrapf(UnexpectedCondition, "invalid coverage mode: %d", mode)
+	}
+	wr.coverage = mode
+	wr.written = wr.written[:0]
+	wr.overlapped = wr.overlapped[:0]
+	if mode != CoverageOff && wr.offset > 0 {
+		wr.markCoverage(0, wr.offset, true)
+	}
+	return nil
+}
+
+// Coverage returns the ranges of bits before the current offset that were never written, and the ones
+// written more than once. Bits reserved with Reserve count as written only once their Reservation is set.
+// Both are nil when coverage tracking is off.
+func (wr *Writer) Coverage() (gaps, overlaps []BitRange) {
+	if wr.coverage == CoverageOff {
+		return nil, nil
+	}
+	return bitRuns(wr.written, wr.offset, 0), bitRuns(wr.overlapped, wr.offset, 1)
+}
+
+// checkCoverage returns a *CoverageError in CoverageFail mode if there are gaps or overlaps.
+func (wr *Writer) checkCoverage() error {
+	if wr.coverage != CoverageFail {
+		return nil
+	}
+	gaps, overlaps := wr.Coverage()
+	if len(gaps) == 0 && len(overlaps) == 0 {
+		return nil
+	}
+	return &CoverageError{Gaps: gaps, Overlaps: overlaps}
+}
+
+// markCoverage records the nBits bits at offsetInBits as written, or as reserved but not written yet.
+// Bits at or past the offset are not part of the stream yet, whatever a failed write left in the bitmaps,
+// so they are counted from scratch. Bits before the offset that were already written become overlaps.
+func (wr *Writer) markCoverage(offsetInBits, nBits int, written bool) {
+	if wr.coverage == CoverageOff {
+		return
+	}
+	for need := bitsToWordSize(offsetInBits + nBits); len(wr.written) < need; {
+		wr.written = append(wr.written, 0)
+		wr.overlapped = append(wr.overlapped, 0)
+	}
+	appended := offsetInBits >= wr.offset
+	for nBits > 0 {
+		width := nBits
+		if width > 64 {
+			width = 64
+		}
+		// The bitmaps are sized for the field above, so the accesses cannot fail.
+		var done, overlap uint64
+		if !appended {
+			done, _ = Get64BitsFieldFromSlice(wr.written, uint64(width), uint64(offsetInBits))
+			overlap, _ = Get64BitsFieldFromSlice(wr.overlapped, uint64(width), uint64(offsetInBits))
+		}
+		if written {
+			overlap |= done
+			done = ^uint64(0)
+		} else {
+			done = 0
+		}
+		_, _ = Set64BitsFieldToSlice(wr.written, done, uint64(width), uint64(offsetInBits))
+		_, _ = Set64BitsFieldToSlice(wr.overlapped, overlap, uint64(width), uint64(offsetInBits))
+		offsetInBits += width
+		nBits -= width
+	}
+}
+
+// bitRuns returns the ranges of the first nBits bits of words equal to bit. Words missing from words count as zero.
+func bitRuns(words []uint64, nBits int, bit uint64) (runs []BitRange) {
+	start := -1
+	for pos := 0; pos < nBits; pos++ {
+		var word uint64
+		if pos/64 < len(words) {
+			word = words[pos/64]
+		}
+		if word>>(pos%64)&1 == bit {
+			if start < 0 {
+				start = pos
+			}
+		} else if start >= 0 {
+			runs = append(runs, BitRange{Offset: start, Width: pos - start})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		runs = append(runs, BitRange{Offset: start, Width: nBits - start})
+	}
+	return runs
+}
@@ -0,0 +1,133 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestWriterCoverage(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		build    func(w *gobitstream.Writer) error
+		gaps     []gobitstream.BitRange
+		overlaps []gobitstream.BitRange
+	}{
+		{
+			name: "complete frame",
+			build: func(w *gobitstream.Writer) error {
+				a.Nil(w.WriteNbitsFromWord(4, 0x4))
+				length, err := w.Reserve(12)
+				a.Nil(err)
+				a.Nil(w.WriteNbitsFromBytes(80, make([]byte, 10)))
+				return length.Set(80)
+			},
+		},
+		{
+			name: "reservation never set",
+			build: func(w *gobitstream.Writer) error {
+				a.Nil(w.WriteNbitsFromWord(4, 0x4))
+				_, err := w.Reserve(12)
+				a.Nil(err)
+				return w.WriteNbitsFromWord(16, 0xFFFF)
+			},
+			gaps: []gobitstream.BitRange{{Offset: 4, Width: 12}},
+		},
+		{
+			name: "field patched twice",
+			build: func(w *gobitstream.Writer) error {
+				a.Nil(w.WriteNbitsFromWord(64, 0))
+				a.Nil(w.WriteNbitsFromWord(40, 0))
+				a.Nil(w.WriteBitsAt(60, 8, 0xAA))
+				return w.WriteBitsAt(100, 4, 0x5)
+			},
+			overlaps: []gobitstream.BitRange{{Offset: 60, Width: 8}, {Offset: 100, Width: 4}},
+		},
+		{
+			name: "reservation set twice",
+			build: func(w *gobitstream.Writer) error {
+				checksum, err := w.Reserve(8)
+				a.Nil(err)
+				a.Nil(w.WriteNbitsFromWord(8, 0x12))
+				a.Nil(checksum.Set(0x12))
+				return checksum.Set(0x13)
+			},
+			overlaps: []gobitstream.BitRange{{Offset: 0, Width: 8}},
+		},
+		{
+			name: "failed write is not counted",
+			build: func(w *gobitstream.Writer) error {
+				a.Nil(w.WriteNbitsFromWord(64, 0))
+				a.Nil(w.WriteNbitsFromWord(46, 0))
+				a.NotNil(w.WriteUE(1000))
+				return w.WriteNbitsFromWord(18, 0x3FFFF)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+				w, err := gobitstream.NewWriterWithOrder(128, gobitstream.BigEndian, bitOrder)
+				a.Nil(err)
+				a.Nil(w.SetCoverage(gobitstream.CoverageFail))
+				a.Nil(tc.build(w))
+
+				gaps, overlaps := w.Coverage()
+				a.Equal(tc.gaps, gaps)
+				a.Equal(tc.overlaps, overlaps)
+
+				err = w.Flush()
+				if tc.gaps == nil && tc.overlaps == nil {
+					a.Nil(err)
+					continue
+				}
+				coverage, ok := err.(*gobitstream.CoverageError)
+				a.True(ok)
+				a.Equal(&gobitstream.CoverageError{Gaps: tc.gaps, Overlaps: tc.overlaps}, coverage)
+				a.NotEmpty(w.Bytes())
+			}
+		})
+	}
+}
+
+func TestWriterCoverageModes(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewGrowableWriterLE()
+	a.Nil(w.WriteNbitsFromWord(8, 0xFF))
+	gaps, overlaps := w.Coverage()
+	a.Nil(gaps)
+	a.Nil(overlaps)
+	a.Equal(gobitstream.UnexpectedCondition, errors.Cause(w.SetCoverage(gobitstream.CoverageMode(3))))
+
+	a.Nil(w.SetCoverage(gobitstream.CoverageReport))
+	_, err := w.Reserve(8)
+	a.Nil(err)
+	a.Nil(w.WriteBitsAt(4, 4, 0))
+	gaps, overlaps = w.Coverage()
+	a.Equal([]gobitstream.BitRange{{Offset: 8, Width: 8}}, gaps)
+	a.Equal([]gobitstream.BitRange{{Offset: 4, Width: 4}}, overlaps)
+	a.Nil(w.Flush())
+
+	w.Reset()
+	a.Nil(w.WriteNbitsFromWord(16, 0))
+	gaps, overlaps = w.Coverage()
+	a.Nil(gaps)
+	a.Nil(overlaps)
+
+	buffer := make([]byte, 8)
+	w, err = gobitstream.NewWriterOn(buffer, 5, 48, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	a.Nil(w.SetCoverage(gobitstream.CoverageFail))
+	_, err = w.Reserve(16)
+	a.Nil(err)
+	a.Nil(w.WriteNbitsFromBytes(32, []byte{1, 2, 3, 4}))
+	err = w.Flush()
+	a.Equal("bit stream coverage: 1 gaps [[0, 16)], 0 overlaps []", err.Error())
+}
@@ -1,6 +1,8 @@
 package gobitstream
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 )
 
@@ -21,3 +23,44 @@ var InvalidResultAssertionError = errors.New("invalid result assertion")
 var CaseWIPError = errors.New("case not supported yet")
 
 var UnexpectedCondition = errors.New("unexpected condition")
+
+var CodeOverflowError = errors.New("variable length code overflows 64 bits")
+
+var TruncatedCodeError = errors.New("variable length code is truncated")
+
+var InvalidPaddingError = errors.New("invalid padding bits")
+
+var FixedPointOverflowError = errors.New("value does not fit in the fixed point format")
+
+var InvalidBCDDigitError = errors.New("invalid BCD digit")
+
+var InvalidOneHotError = errors.New("invalid one-hot value")
+
+var InvalidThermometerError = errors.New("invalid thermometer value")
+
+// FieldOverflowError is returned when a value does not fit in the width of the field it is written to.
+// It is returned without a stack wrapper so callers can type-assert it directly.
+type FieldOverflowError struct {
+	Offset uint64 // Offset of the field in bits
+	Width  uint64 // Width of the field in bits
+	Value  uint64 // Offending value, as a two's complement bit pattern when Signed is set
+	Signed bool   // Whether the field is interpreted as a signed two's complement value
+}
+
+func (e *FieldOverflowError) Error() string {
+	if e.Signed {
+		return fmt.Sprintf("value %d does not fit in a signed %d bits field at offset %d", int64(e.Value), e.Width, e.Offset)
+	}
+	return fmt.Sprintf("value %d does not fit in an unsigned %d bits field at offset %d", e.Value, e.Width, e.Offset)
+}
+
+// CoverageError is returned by Writer.Flush in CoverageFail mode when bits of the stream were never written,
+// or were written more than once. It is returned without a stack wrapper so callers can type-assert it directly.
+type CoverageError struct {
+	Gaps     []BitRange // Ranges of bits never written
+	Overlaps []BitRange // Ranges of bits written more than once
+}
+
+func (e *CoverageError) Error() string {
+	return fmt.Sprintf("bit stream coverage: %d gaps %v, %d overlaps %v", len(e.Gaps), e.Gaps, len(e.Overlaps), e.Overlaps)
+}
@@ -0,0 +1,152 @@
+package gobitstream
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// Exponential-Golomb codes, as used by H.264/HEVC ue(v) and se(v) syntax elements.
+//
+// A k-th order code for value v writes x = v + 2^k in binary, preceded by as many zero bits as x has bits
+// after its leading one minus k. Codes for values close to 2^64 need more than 64 bits, so the code value x
+// is carried around as a 65 bits number split in hi and lo words.
+
+// checkExpGolombOrder validates the order of an Exp-Golomb code.
+func checkExpGolombOrder(k int) error {
+	if k < 0 || k > 63 {
+		return errors.Wrapf(InvalidWidthError, "exp-golomb order must be between 0 and 63, got: %d", k)
+	}
+	return nil
+}
+
+// readExpGolombCode reads a k-th order Exp-Golomb code and returns the decoded value as a 65 bits number.
+// On error the offset is left where the code starts.
+func (wr *Reader) readExpGolombCode(k int) (hi, lo uint64, err error) {
+	zeros, terminated := wr.runLength(0)
+	if !terminated {
+		return 0, 0, errors.Wrapf(TruncatedCodeError, "exp-golomb prefix at offset: %d", wr.offset)
+	}
+
+	infoBits := zeros + k
+	if infoBits > 64 {
+		return 0, 0, errors.Wrapf(CodeOverflowError, "exp-golomb code with %d info bits at offset: %d", infoBits, wr.offset)
+	}
+	if zeros+1+infoBits > wr.Remaining() {
+		return 0, 0, errors.Wrapf(TruncatedCodeError, "exp-golomb suffix at offset: %d", wr.offset)
+	}
+
+	start := wr.offset
+	wr.offset += zeros + 1
+
+	var info uint64
+	if infoBits > 0 {
+		if info, err = wr.ReadNbitsUint64(infoBits); err != nil {
+			wr.offset = start
+			return 0, 0, err
+		}
+	}
+
+	// value = 2^infoBits - 2^k + info
+	base := -(uint64(1) << k)
+	if infoBits < 64 {
+		base = uint64(1)<<infoBits - uint64(1)<<k
+	}
+	lo, hi = bits.Add64(base, info, 0)
+	return hi, lo, nil
+}
+
+// ReadExpGolombK reads a k-th order Exp-Golomb coded unsigned value. k must be between 0 and 63.
+// A CodeOverflowError is returned if the value does not fit in 64 bits, and a TruncatedCodeError if the bit stream
+// ends in the middle of the code. On error nothing is consumed.
+func (wr *Reader) ReadExpGolombK(k int) (uint64, error) {
+	if err := checkExpGolombOrder(k); err != nil {
+		return 0, err
+	}
+	start := wr.offset
+	hi, lo, err := wr.readExpGolombCode(k)
+	if err != nil {
+		return 0, err
+	}
+	if hi != 0 {
+		wr.offset = start
+		return 0, errors.Wrapf(CodeOverflowError, "exp-golomb code at offset: %d", start)
+	}
+	return lo, nil
+}
+
+// ReadUE reads an unsigned Exp-Golomb coded value, the ue(v) descriptor of H.264/HEVC.
+func (wr *Reader) ReadUE() (uint64, error) {
+	return wr.ReadExpGolombK(0)
+}
+
+// ReadSE reads a signed Exp-Golomb coded value, the se(v) descriptor of H.264/HEVC.
+// Code values 1, 2, 3, 4... map to 1, -1, 2, -2...
+func (wr *Reader) ReadSE() (int64, error) {
+	start := wr.offset
+	hi, lo, err := wr.readExpGolombCode(0)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case hi != 0 && lo == 0:
+		return math.MinInt64, nil
+	case hi != 0 || lo == math.MaxUint64:
+		wr.offset = start
+		return 0, errors.Wrapf(CodeOverflowError, "signed exp-golomb code at offset: %d", start)
+	case lo&1 == 1:
+		return int64(lo>>1) + 1, nil
+	default:
+		return -int64(lo >> 1), nil
+	}
+}
+
+// writeExpGolombCode writes the k-th order Exp-Golomb code of the 65 bits number x = hi:lo, where x = value + 2^k.
+// On error the offset is restored to where the code starts.
+func (wr *Writer) writeExpGolombCode(k int, hi, lo uint64) error {
+	infoBits := 64
+	if hi == 0 {
+		infoBits = bits.Len64(lo) - 1
+	}
+
+	start := wr.offset
+	err := wr.writeRun(0, infoBits-k)
+	if err == nil {
+		err = wr.WriteNbitsFromWord(1, 1)
+	}
+	if err == nil && infoBits > 0 {
+		err = wr.writeNbits(infoBits, lo)
+	}
+	if err != nil {
+		wr.offset = start
+		return err
+	}
+	return nil
+}
+
+// WriteExpGolombK writes val as a k-th order Exp-Golomb code. k must be between 0 and 63.
+// Values close to 2^64 produce codes longer than 64 bits, which are written as several fields.
+func (wr *Writer) WriteExpGolombK(k int, val uint64) error {
+	if err := checkExpGolombOrder(k); err != nil {
+		return err
+	}
+	lo, hi := bits.Add64(val, uint64(1)<<k, 0)
+	return wr.writeExpGolombCode(k, hi, lo)
+}
+
+// WriteUE writes val as an unsigned Exp-Golomb code, the ue(v) descriptor of H.264/HEVC.
+func (wr *Writer) WriteUE(val uint64) error {
+	return wr.WriteExpGolombK(0, val)
+}
+
+// WriteSE writes val as a signed Exp-Golomb code, the se(v) descriptor of H.264/HEVC.
+// Values 1, -1, 2, -2... map to code values 1, 2, 3, 4...
+func (wr *Writer) WriteSE(val int64) error {
+	// x = codeNum + 1, which is 2*val for positive values and 2*|val| + 1 otherwise.
+	if val > 0 {
+		return wr.writeExpGolombCode(0, 0, uint64(val)<<1)
+	}
+	m := uint64(-val)
+	return wr.writeExpGolombCode(0, m>>63, m<<1|1)
+}
@@ -0,0 +1,147 @@
+package gobitstream_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+	"github.com/pkg/errors"
+)
+
+func TestWriteUELayout(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		val      uint64
+		bits     int
+		expected uint64
+	}{
+		{val: 0, bits: 1, expected: 0x1},
+		{val: 1, bits: 3, expected: 0x2},
+		{val: 2, bits: 3, expected: 0x6},
+		{val: 3, bits: 5, expected: 0x04},
+		{val: 6, bits: 5, expected: 0x1C},
+		{val: 7, bits: 7, expected: 0x08},
+	}
+
+	for _, tc := range testCases {
+		w := gobitstream.NewWriterLE(64)
+		a.Nil(w.WriteUE(tc.val))
+		a.Equal(tc.expected, w.Uint64(), "val: %d", tc.val)
+
+		a.Nil(w.Flush())
+		rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+		a.Nil(err)
+		v, err := rd.ReadUE()
+		a.Nil(err)
+		a.Equal(tc.val, v)
+		a.Equal(tc.bits, rd.Offset())
+	}
+}
+
+func TestExpGolombRoundTrip(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	unsigned := []uint64{0, 1, 2, 1000, math.MaxUint32, math.MaxUint64 - 1, math.MaxUint64}
+	signed := []int64{0, 1, -1, 2, -2, 12345, -12345, math.MaxInt64, math.MinInt64, math.MinInt64 + 1}
+	for i := 0; i < 200; i++ {
+		unsigned = append(unsigned, r.Uint64()>>uint(r.Intn(64)))
+		signed = append(signed, int64(r.Uint64())>>uint(r.Intn(64)))
+	}
+
+	for k := 0; k < 64; k += 7 {
+		w := gobitstream.NewGrowableWriterLE()
+		for _, v := range unsigned {
+			a.Nil(w.WriteExpGolombK(k, v))
+		}
+		for _, v := range signed {
+			a.Nil(w.WriteSE(v))
+		}
+		a.Nil(w.Flush())
+
+		rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+		a.Nil(err)
+		for _, expected := range unsigned {
+			v, err := rd.ReadExpGolombK(k)
+			a.Nil(err)
+			a.Equal(expected, v, "k: %d", k)
+		}
+		for _, expected := range signed {
+			v, err := rd.ReadSE()
+			a.Nil(err)
+			a.Equal(expected, v)
+		}
+		a.Less(rd.Remaining(), 8)
+	}
+}
+
+func TestExpGolombLongCodes(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewGrowableWriterLE()
+	a.Nil(w.WriteUE(math.MaxUint64))
+	a.Nil(w.WriteSE(math.MinInt64))
+	a.Nil(w.WriteExpGolombK(63, math.MaxUint64))
+	a.Nil(w.Flush())
+
+	rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+	a.Nil(err)
+	_, err = rd.ReadUE()
+	a.Nil(err)
+	a.Equal(129, rd.Offset())
+	_, err = rd.ReadSE()
+	a.Nil(err)
+	a.Equal(129+129, rd.Offset())
+	_, err = rd.ReadExpGolombK(63)
+	a.Nil(err)
+	a.Equal(129+129+66, rd.Offset())
+}
+
+func TestExpGolombReadErrors(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	// 65 leading zeros: the value cannot fit in 64 bits.
+	w := gobitstream.NewGrowableWriterLE()
+	a.Nil(w.WriteNbitsFromWord(64, 0))
+	a.Nil(w.WriteNbitsFromWord(2, 0x2))
+	a.Nil(w.WriteNbitsFromWord(64, 0))
+	a.Nil(w.WriteNbitsFromWord(1, 0))
+	a.Nil(w.Flush())
+	rd, err := gobitstream.NewReaderLE(130, w.Bytes())
+	a.Nil(err)
+	_, err = rd.ReadUE()
+	a.Equal(gobitstream.CodeOverflowError, errors.Cause(err))
+	a.Equal(0, rd.Offset())
+
+	// 64 leading zeros with a non zero suffix: 2^64 - 1 + 1 only fits as the se(v) of math.MinInt64.
+	w = gobitstream.NewGrowableWriterLE()
+	a.Nil(w.WriteNbitsFromWord(64, 0))
+	a.Nil(w.WriteNbitsFromWord(1, 1))
+	a.Nil(w.WriteNbitsFromWord(64, 1))
+	a.Nil(w.Flush())
+	rd, err = gobitstream.NewReaderLE(129, w.Bytes())
+	a.Nil(err)
+	_, err = rd.ReadUE()
+	a.Equal(gobitstream.CodeOverflowError, errors.Cause(err))
+	a.Equal(0, rd.Offset())
+	v, err := rd.ReadSE()
+	a.Nil(err)
+	a.Equal(int64(math.MinInt64), v)
+
+	// Only zeros: the prefix never terminates.
+	rd, err = gobitstream.NewReaderLE(16, []byte{0, 0})
+	a.Nil(err)
+	_, err = rd.ReadUE()
+	a.Equal(gobitstream.TruncatedCodeError, errors.Cause(err))
+
+	// The suffix is cut by the end of the bit stream.
+	rd, err = gobitstream.NewReaderLE(4, []byte{0x04})
+	a.Nil(err)
+	_, err = rd.ReadUE()
+	a.Equal(gobitstream.TruncatedCodeError, errors.Cause(err))
+	a.Equal(0, rd.Offset())
+
+	_, err = rd.ReadExpGolombK(64)
+	a.NotNil(err)
+}
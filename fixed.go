@@ -0,0 +1,260 @@
+package gobitstream
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// QFormat describes a fixed point format in Q notation. IntBits includes the sign bit of signed formats,
+// so Q1.15 is QFormat{IntBits: 1, FracBits: 15, Signed: true} and is 16 bits wide.
+type QFormat struct {
+	IntBits  int
+	FracBits int
+	Signed   bool
+}
+
+// Width returns the width in bits of the format.
+func (f QFormat) Width() int {
+	return f.IntBits + f.FracBits
+}
+
+func (f QFormat) String() string {
+	if f.Signed {
+		return fmt.Sprintf("Q%d.%d", f.IntBits, f.FracBits)
+	}
+	return fmt.Sprintf("UQ%d.%d", f.IntBits, f.FracBits)
+}
+
+func (f QFormat) validate() error {
+	if f.IntBits < 0 || f.FracBits < 0 || f.Width() <= 0 || f.Width() > 64 {
+		return errors.Wrapf(InvalidWidthError, "invalid fixed point format: %s", f)
+	}
+	return nil
+}
+
+// limits returns the smallest and the largest raw value of the format.
+func (f QFormat) limits() (lo, hi *big.Int) {
+	width := uint(f.Width())
+	if f.Signed {
+		hi = new(big.Int).Lsh(big.NewInt(1), width-1)
+		lo = new(big.Int).Neg(hi)
+		return lo, hi.Sub(hi, big.NewInt(1))
+	}
+	hi = new(big.Int).Lsh(big.NewInt(1), width)
+	return new(big.Int), hi.Sub(hi, big.NewInt(1))
+}
+
+// widthMask returns a mask of the width low bits, for widths up to 64.
+func widthMask(width int) uint64 {
+	return ^uint64(0) >> (64 - width)
+}
+
+// Rounding selects how the bits that do not fit in the fraction of a fixed point format are discarded.
+type Rounding int
+
+const (
+	// RoundTruncate drops the extra bits, which rounds towards negative infinity as a hardware truncation does.
+	RoundTruncate Rounding = iota
+	// RoundNearestEven rounds to the nearest value, and ties to the value with an even least significant bit.
+	RoundNearestEven
+	// RoundNearestAway rounds to the nearest value, and ties away from zero.
+	RoundNearestAway
+)
+
+// Overflow selects what happens to a value outside of the range of a fixed point format.
+type Overflow int
+
+const (
+	// OverflowWrap keeps the low bits of the value, as a two's complement adder does.
+	OverflowWrap Overflow = iota
+	// OverflowSaturate clamps the value to the smallest or the largest value of the format.
+	OverflowSaturate
+	// OverflowError returns a FixedPointOverflowError.
+	OverflowError
+)
+
+// FixedPoint is a fixed point value. Raw holds its bits, Format.Width() bits wide, as they are stored in a field.
+type FixedPoint struct {
+	Format QFormat
+	Raw    uint64
+}
+
+// NewFixedPoint creates a FixedPoint of the given format from its raw bits. An error is returned if the format is
+// invalid or raw does not fit in its width.
+func NewFixedPoint(format QFormat, raw uint64) (FixedPoint, error) {
+	if err := format.validate(); err != nil {
+		return FixedPoint{}, err
+	}
+	if raw&^widthMask(format.Width()) != 0 {
+		return FixedPoint{}, errors.Wrapf(FixedPointOverflowError, "raw value %#x does not fit in %s", raw, format)
+	}
+	return FixedPoint{Format: format, Raw: raw}, nil
+}
+
+// FixedFromFloat64 converts val to the given format with the given rounding and overflow modes.
+// NaN can not be converted, and infinities are clamped by OverflowSaturate and rejected by the other overflow modes.
+func FixedFromFloat64(format QFormat, val float64, rounding Rounding, overflow Overflow) (FixedPoint, error) {
+	if err := format.validate(); err != nil {
+		return FixedPoint{}, err
+	}
+	if math.IsNaN(val) {
+		return FixedPoint{}, errors.Wrapf(UnexpectedCondition, "NaN has no %s representation", format)
+	}
+	if math.IsInf(val, 0) {
+		if overflow != OverflowSaturate {
+			return FixedPoint{}, errors.Wrapf(FixedPointOverflowError, "%g does not fit in %s", val, format)
+		}
+		lo, hi := format.limits()
+		if val < 0 {
+			return FixedPoint{Format: format, Raw: uint64(lo.Int64()) & widthMask(format.Width())}, nil
+		}
+		return FixedPoint{Format: format, Raw: hi.Uint64()}, nil
+	}
+	// val is exactly mant * 2^(exp-53).
+	frac, exp := math.Frexp(val)
+	mant := big.NewInt(int64(frac * (1 << 53)))
+	return quantizeFixed(mant, 53-exp, format, rounding, overflow)
+}
+
+// Int64 returns the raw bits of x as an integer, sign-extended for signed formats.
+func (x FixedPoint) Int64() int64 {
+	if x.Format.Signed {
+		return signExtend(x.Raw, uint64(x.Format.Width()))
+	}
+	return int64(x.Raw)
+}
+
+// Float64 returns the value of x as a float64, rounded to nearest even if it has more than 53 significant bits.
+func (x FixedPoint) Float64() float64 {
+	f, _ := new(big.Float).SetMantExp(new(big.Float).SetInt(x.bigInt()), -x.Format.FracBits).Float64()
+	return f
+}
+
+func (x FixedPoint) String() string {
+	return fmt.Sprintf("%g (%s %#x)", x.Float64(), x.Format, x.Raw)
+}
+
+// Convert converts x to the out format with the given rounding and overflow modes.
+func (x FixedPoint) Convert(out QFormat, rounding Rounding, overflow Overflow) (FixedPoint, error) {
+	if err := out.validate(); err != nil {
+		return FixedPoint{}, err
+	}
+	return quantizeFixed(x.bigInt(), x.Format.FracBits, out, rounding, overflow)
+}
+
+// Add returns x + y in the out format. The sum is computed exactly and then rounded and checked for overflow,
+// which is bit exact with a hardware adder wide enough for both operands followed by a quantizer.
+func (x FixedPoint) Add(y FixedPoint, out QFormat, rounding Rounding, overflow Overflow) (FixedPoint, error) {
+	return x.addSub(y, false, out, rounding, overflow)
+}
+
+// Sub returns x - y in the out format, as Add does.
+func (x FixedPoint) Sub(y FixedPoint, out QFormat, rounding Rounding, overflow Overflow) (FixedPoint, error) {
+	return x.addSub(y, true, out, rounding, overflow)
+}
+
+// Mul returns x * y in the out format. The full precision product, with the fraction bits of both operands,
+// is computed exactly and then rounded and checked for overflow.
+func (x FixedPoint) Mul(y FixedPoint, out QFormat, rounding Rounding, overflow Overflow) (FixedPoint, error) {
+	if err := out.validate(); err != nil {
+		return FixedPoint{}, err
+	}
+	product := new(big.Int).Mul(x.bigInt(), y.bigInt())
+	return quantizeFixed(product, x.Format.FracBits+y.Format.FracBits, out, rounding, overflow)
+}
+
+func (x FixedPoint) addSub(y FixedPoint, sub bool, out QFormat, rounding Rounding, overflow Overflow) (FixedPoint, error) {
+	if err := out.validate(); err != nil {
+		return FixedPoint{}, err
+	}
+	xv, yv := x.bigInt(), y.bigInt()
+	frac := x.Format.FracBits
+	if y.Format.FracBits > frac {
+		frac = y.Format.FracBits
+	}
+	xv.Lsh(xv, uint(frac-x.Format.FracBits))
+	yv.Lsh(yv, uint(frac-y.Format.FracBits))
+	if sub {
+		return quantizeFixed(xv.Sub(xv, yv), frac, out, rounding, overflow)
+	}
+	return quantizeFixed(xv.Add(xv, yv), frac, out, rounding, overflow)
+}
+
+func (x FixedPoint) bigInt() *big.Int {
+	if x.Format.Signed {
+		return big.NewInt(x.Int64())
+	}
+	return new(big.Int).SetUint64(x.Raw)
+}
+
+// quantizeFixed converts the exact value val * 2^-frac to the out format. frac may be negative.
+func quantizeFixed(val *big.Int, frac int, out QFormat, rounding Rounding, overflow Overflow) (FixedPoint, error) {
+	val = new(big.Int).Set(val)
+	shift := frac - out.FracBits
+	if shift < 0 {
+		val.Lsh(val, uint(-shift))
+	} else if shift > 0 {
+		exact := new(big.Int).Set(val)
+		val.Rsh(val, uint(shift)) // Rounds towards negative infinity.
+		rem := new(big.Int).Sub(exact, new(big.Int).Lsh(val, uint(shift)))
+		half := new(big.Int).Lsh(big.NewInt(1), uint(shift-1))
+		var roundUp bool
+		switch rounding {
+		case RoundTruncate:
+		case RoundNearestEven:
+			cmp := rem.Cmp(half)
+			roundUp = cmp > 0 || (cmp == 0 && val.Bit(0) == 1)
+		case RoundNearestAway:
+			cmp := rem.Cmp(half)
+			roundUp = cmp > 0 || (cmp == 0 && exact.Sign() >= 0)
+		default:
+			return FixedPoint{}, errors.Wrapf(UnexpectedCondition, "invalid rounding mode: %d", rounding)
+		}
+		if roundUp {
+			val.Add(val, big.NewInt(1))
+		}
+	}
+
+	lo, hi := out.limits()
+	if val.Cmp(lo) < 0 || val.Cmp(hi) > 0 {
+		switch overflow {
+		case OverflowWrap:
+		case OverflowSaturate:
+			if val.Sign() < 0 {
+				val = lo
+			} else {
+				val = hi
+			}
+		case OverflowError:
+			return FixedPoint{}, errors.Wrapf(FixedPointOverflowError, "%s does not fit in %s",
+				new(big.Float).SetMantExp(new(big.Float).SetInt(val), -out.FracBits).Text('g', 10), out)
+		default:
+			return FixedPoint{}, errors.Wrapf(UnexpectedCondition, "invalid overflow mode: %d", overflow)
+		}
+	}
+	mask := new(big.Int).SetUint64(widthMask(out.Width()))
+	return FixedPoint{Format: out, Raw: val.And(val, mask).Uint64()}, nil
+}
+
+// ReadFixed reads a fixed point value of the given format from the bit stream.
+func (wr *Reader) ReadFixed(format QFormat) (FixedPoint, error) {
+	if err := format.validate(); err != nil {
+		return FixedPoint{}, err
+	}
+	raw, err := wr.ReadNbitsUint64(format.Width())
+	if err != nil {
+		return FixedPoint{}, err
+	}
+	return FixedPoint{Format: format, Raw: raw}, nil
+}
+
+// WriteFixed writes the raw bits of val, Format.Width() bits wide, to the bit stream.
+func (wr *Writer) WriteFixed(val FixedPoint) error {
+	if _, err := NewFixedPoint(val.Format, val.Raw); err != nil {
+		return err
+	}
+	return wr.WriteNbitsFromWord(val.Format.Width(), val.Raw)
+}
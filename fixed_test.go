@@ -0,0 +1,177 @@
+package gobitstream_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+var (
+	q1_15  = gobitstream.QFormat{IntBits: 1, FracBits: 15, Signed: true}
+	uq4_12 = gobitstream.QFormat{IntBits: 4, FracBits: 12}
+	q3_20  = gobitstream.QFormat{IntBits: 3, FracBits: 20, Signed: true}
+	q8_0   = gobitstream.QFormat{IntBits: 8, FracBits: 0, Signed: true}
+)
+
+func TestFixedFromFloat64(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		format   gobitstream.QFormat
+		val      float64
+		rounding gobitstream.Rounding
+		overflow gobitstream.Overflow
+		expected uint64
+	}{
+		{name: "half", format: q1_15, val: 0.5, expected: 0x4000},
+		{name: "minus one", format: q1_15, val: -1, expected: 0x8000},
+		{name: "one wraps", format: q1_15, val: 1, expected: 0x8000},
+		{name: "one saturates", format: q1_15, val: 1, overflow: gobitstream.OverflowSaturate, expected: 0x7FFF},
+		{name: "minus two saturates", format: q1_15, val: -2, overflow: gobitstream.OverflowSaturate, expected: 0x8000},
+		{name: "unsigned negative saturates", format: uq4_12, val: -0.1, overflow: gobitstream.OverflowSaturate, expected: 0},
+		{name: "pi truncated", format: uq4_12, val: math.Pi, expected: 0x3243},
+		{name: "pi nearest", format: uq4_12, val: math.Pi, rounding: gobitstream.RoundNearestEven, expected: 0x3244},
+		{name: "tie truncated", format: q8_0, val: 2.5, expected: 2},
+		{name: "tie nearest even", format: q8_0, val: 2.5, rounding: gobitstream.RoundNearestEven, expected: 2},
+		{name: "tie nearest away", format: q8_0, val: 2.5, rounding: gobitstream.RoundNearestAway, expected: 3},
+		{name: "negative tie truncated", format: q8_0, val: -2.5, expected: 0xFD},
+		{name: "negative tie nearest even", format: q8_0, val: -2.5, rounding: gobitstream.RoundNearestEven, expected: 0xFE},
+		{name: "negative tie nearest away", format: q8_0, val: -2.5, rounding: gobitstream.RoundNearestAway, expected: 0xFD},
+		{name: "odd tie nearest even", format: q8_0, val: 3.5, rounding: gobitstream.RoundNearestEven, expected: 4},
+		{name: "rounding overflows", format: q1_15, val: 0.99999, rounding: gobitstream.RoundNearestEven, overflow: gobitstream.OverflowSaturate, expected: 0x7FFF},
+		{name: "infinity saturates", format: q3_20, val: math.Inf(-1), overflow: gobitstream.OverflowSaturate, expected: 0x400000},
+		{name: "zero", format: q3_20, val: 0, expected: 0},
+		{name: "full width", format: gobitstream.QFormat{IntBits: 64}, val: math.Ldexp(1, 63), expected: 1 << 63},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			x, err := gobitstream.FixedFromFloat64(tc.format, tc.val, tc.rounding, tc.overflow)
+			a.Nil(err)
+			a.Equal(tc.format, x.Format)
+			a.Equal(tc.expected, x.Raw)
+		})
+	}
+
+	_, err := gobitstream.FixedFromFloat64(q1_15, 1, gobitstream.RoundTruncate, gobitstream.OverflowError)
+	a.Equal(gobitstream.FixedPointOverflowError, errors.Cause(err))
+	_, err = gobitstream.FixedFromFloat64(q1_15, math.Inf(1), gobitstream.RoundTruncate, gobitstream.OverflowWrap)
+	a.Equal(gobitstream.FixedPointOverflowError, errors.Cause(err))
+	_, err = gobitstream.FixedFromFloat64(q1_15, math.NaN(), gobitstream.RoundTruncate, gobitstream.OverflowSaturate)
+	a.NotNil(err)
+	_, err = gobitstream.FixedFromFloat64(gobitstream.QFormat{IntBits: 40, FracBits: 25}, 1, gobitstream.RoundTruncate, gobitstream.OverflowWrap)
+	a.Equal(gobitstream.InvalidWidthError, errors.Cause(err))
+	_, err = gobitstream.FixedFromFloat64(gobitstream.QFormat{}, 1, gobitstream.RoundTruncate, gobitstream.OverflowWrap)
+	a.NotNil(err)
+}
+
+func TestFixedPointFloat64RoundTrip(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	for _, format := range []gobitstream.QFormat{q1_15, uq4_12, q3_20, q8_0, {IntBits: 0, FracBits: 53}} {
+		for i := 0; i < 1000; i++ {
+			x, err := gobitstream.NewFixedPoint(format, r.Uint64()>>(64-format.Width()))
+			a.Nil(err)
+			y, err := gobitstream.FixedFromFloat64(format, x.Float64(), gobitstream.RoundTruncate, gobitstream.OverflowError)
+			a.Nil(err)
+			a.Equal(x, y)
+		}
+	}
+
+	x, err := gobitstream.NewFixedPoint(q3_20, 0x700000)
+	a.Nil(err)
+	a.Equal(-1.0, x.Float64())
+	a.Equal(int64(-0x100000), x.Int64())
+	a.Equal("-1 (Q3.20 0x700000)", x.String())
+
+	_, err = gobitstream.NewFixedPoint(q1_15, 0x10000)
+	a.Equal(gobitstream.FixedPointOverflowError, errors.Cause(err))
+}
+
+func TestFixedPointArithmetic(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	fixed := func(format gobitstream.QFormat, val float64) gobitstream.FixedPoint {
+		x, err := gobitstream.FixedFromFloat64(format, val, gobitstream.RoundTruncate, gobitstream.OverflowError)
+		a.Nil(err)
+		return x
+	}
+	q2_30 := gobitstream.QFormat{IntBits: 2, FracBits: 30, Signed: true}
+
+	testCases := []struct {
+		name     string
+		op       func(x, y gobitstream.FixedPoint, out gobitstream.QFormat, rounding gobitstream.Rounding, overflow gobitstream.Overflow) (gobitstream.FixedPoint, error)
+		x, y     gobitstream.FixedPoint
+		out      gobitstream.QFormat
+		rounding gobitstream.Rounding
+		overflow gobitstream.Overflow
+		expected uint64
+	}{
+		{name: "add", op: gobitstream.FixedPoint.Add, x: fixed(q1_15, 0.25), y: fixed(q1_15, 0.5), out: q1_15, expected: 0x6000},
+		{name: "add wraps", op: gobitstream.FixedPoint.Add, x: fixed(q1_15, 0.75), y: fixed(q1_15, 0.5), out: q1_15, expected: 0xA000},
+		{name: "add saturates", op: gobitstream.FixedPoint.Add, x: fixed(q1_15, 0.75), y: fixed(q1_15, 0.5), out: q1_15, overflow: gobitstream.OverflowSaturate, expected: 0x7FFF},
+		{name: "add mixed formats", op: gobitstream.FixedPoint.Add, x: fixed(uq4_12, 1.5), y: fixed(q3_20, -0.25), out: q3_20, expected: 0x140000},
+		{name: "sub", op: gobitstream.FixedPoint.Sub, x: fixed(q1_15, -0.5), y: fixed(q1_15, 0.25), out: q1_15, expected: 0xA000},
+		{name: "sub unsigned saturates", op: gobitstream.FixedPoint.Sub, x: fixed(uq4_12, 1), y: fixed(uq4_12, 2), out: uq4_12, overflow: gobitstream.OverflowSaturate, expected: 0},
+		{name: "sub unsigned wraps", op: gobitstream.FixedPoint.Sub, x: fixed(uq4_12, 1), y: fixed(uq4_12, 2), out: uq4_12, expected: 0xF000},
+		{name: "mul", op: gobitstream.FixedPoint.Mul, x: fixed(q1_15, 0.5), y: fixed(q1_15, 0.5), out: q1_15, expected: 0x2000},
+		{name: "mul full precision", op: gobitstream.FixedPoint.Mul, x: fixed(q1_15, -1), y: fixed(q1_15, -1), out: q2_30, expected: 0x40000000},
+		{name: "mul wraps", op: gobitstream.FixedPoint.Mul, x: fixed(q1_15, -1), y: fixed(q1_15, -1), out: q1_15, expected: 0x8000},
+		{name: "mul saturates", op: gobitstream.FixedPoint.Mul, x: fixed(q1_15, -1), y: fixed(q1_15, -1), out: q1_15, overflow: gobitstream.OverflowSaturate, expected: 0x7FFF},
+		// -2^-15 * 0.5 is -2^-16, a tie between -2^-15 and 0.
+		{name: "mul truncates", op: gobitstream.FixedPoint.Mul, x: gobitstream.FixedPoint{Format: q1_15, Raw: 0xFFFF}, y: fixed(q1_15, 0.5), out: q1_15, expected: 0xFFFF},
+		{name: "mul nearest even", op: gobitstream.FixedPoint.Mul, x: gobitstream.FixedPoint{Format: q1_15, Raw: 0xFFFF}, y: fixed(q1_15, 0.5), out: q1_15, rounding: gobitstream.RoundNearestEven, expected: 0},
+		{name: "mul nearest away", op: gobitstream.FixedPoint.Mul, x: gobitstream.FixedPoint{Format: q1_15, Raw: 0xFFFF}, y: fixed(q1_15, 0.5), out: q1_15, rounding: gobitstream.RoundNearestAway, expected: 0xFFFF},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.op(tc.x, tc.y, tc.out, tc.rounding, tc.overflow)
+			a.Nil(err)
+			a.Equal(tc.out, res.Format)
+			a.Equal(tc.expected, res.Raw)
+		})
+	}
+
+	_, err := fixed(q1_15, -1).Sub(fixed(q1_15, 0.5), q1_15, gobitstream.RoundTruncate, gobitstream.OverflowError)
+	a.Equal(gobitstream.FixedPointOverflowError, errors.Cause(err))
+	_, err = fixed(q1_15, 0.5).Mul(fixed(q1_15, 0.5), gobitstream.QFormat{IntBits: -1, FracBits: 8}, gobitstream.RoundTruncate, gobitstream.OverflowError)
+	a.NotNil(err)
+
+	converted, err := fixed(q3_20, -2.75).Convert(q1_15, gobitstream.RoundTruncate, gobitstream.OverflowSaturate)
+	a.Nil(err)
+	a.Equal(uint64(0x8000), converted.Raw)
+}
+
+func TestReadWriteFixed(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	x, err := gobitstream.FixedFromFloat64(q3_20, -2.75, gobitstream.RoundTruncate, gobitstream.OverflowError)
+	a.Nil(err)
+	y, err := gobitstream.FixedFromFloat64(uq4_12, 15.5, gobitstream.RoundTruncate, gobitstream.OverflowError)
+	a.Nil(err)
+
+	w := gobitstream.NewWriterLE(3 + 23 + 16)
+	a.Nil(w.WriteNbitsFromWord(3, 0x7))
+	a.Nil(w.WriteFixed(x))
+	a.Nil(w.WriteFixed(y))
+	a.NotNil(w.WriteFixed(gobitstream.FixedPoint{Format: q1_15, Raw: 0x10000}))
+
+	rd := w.Reader()
+	_, err = rd.ReadNbitsUint64(3)
+	a.Nil(err)
+	actual, err := rd.ReadFixed(q3_20)
+	a.Nil(err)
+	a.Equal(x, actual)
+	a.Equal(-2.75, actual.Float64())
+	actual, err = rd.ReadFixed(uq4_12)
+	a.Nil(err)
+	a.Equal(15.5, actual.Float64())
+	_, err = rd.ReadFixed(gobitstream.QFormat{IntBits: 65})
+	a.NotNil(err)
+}
@@ -0,0 +1,321 @@
+package gobitstream
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// FP8Format selects one of the 8 bits floating point formats of the OCP 8-bit floating point specification.
+type FP8Format int
+
+const (
+	// E4M3 has 4 exponent bits and 3 mantissa bits. It has no infinities and a single NaN mantissa,
+	// which extends its range up to ±448.
+	E4M3 FP8Format = iota
+	// E5M2 has 5 exponent bits and 2 mantissa bits, and follows the IEEE 754 conventions for infinities and NaNs.
+	E5M2
+)
+
+// minifloat describes a binary floating point format narrower than float32.
+type minifloat struct {
+	expBits  int
+	mantBits int
+	// finite is set for formats without infinities, where only the all ones code is a NaN.
+	finite bool
+}
+
+var (
+	float16Format  = minifloat{expBits: 5, mantBits: 10}
+	bfloat16Format = minifloat{expBits: 8, mantBits: 7}
+	e4m3Format     = minifloat{expBits: 4, mantBits: 3, finite: true}
+	e5m2Format     = minifloat{expBits: 5, mantBits: 2}
+)
+
+func (f minifloat) width() int {
+	return 1 + f.expBits + f.mantBits
+}
+
+func (f minifloat) bias() int {
+	return 1<<(f.expBits-1) - 1
+}
+
+func (f minifloat) expMax() uint64 {
+	return 1<<f.expBits - 1
+}
+
+// maxFinite returns the code of the largest finite positive value.
+func (f minifloat) maxFinite() uint64 {
+	if f.finite {
+		return 1<<(f.width()-1) - 2
+	}
+	return f.expMax()<<f.mantBits - 1
+}
+
+// overflow returns the code used for a value that is too large for the format.
+func (f minifloat) overflow(sign uint64, saturate bool) uint64 {
+	switch {
+	case saturate:
+		return sign | f.maxFinite()
+	case f.finite:
+		return sign | (f.maxFinite() + 1)
+	default:
+		return sign | f.expMax()<<f.mantBits
+	}
+}
+
+// decode converts a code of the format to a float32. All the values of the format are exact float32 values,
+// and the payload of NaNs is kept in the top bits of the float32 mantissa.
+func (f minifloat) decode(code uint64) float32 {
+	sign := uint32(code>>(f.width()-1)) & 1
+	exp := (code >> f.mantBits) & f.expMax()
+	mant := code & (1<<f.mantBits - 1)
+
+	isNaN := exp == f.expMax() && mant != 0
+	if f.finite {
+		isNaN = exp == f.expMax() && mant == 1<<f.mantBits-1
+	}
+	switch {
+	case isNaN:
+		return math.Float32frombits(sign<<31 | 0xFF<<23 | uint32(mant)<<(23-f.mantBits))
+	case exp == f.expMax() && !f.finite:
+		return math.Float32frombits(sign<<31 | 0xFF<<23)
+	}
+
+	var val float64
+	if exp == 0 {
+		val = math.Ldexp(float64(mant), 1-f.bias()-f.mantBits)
+	} else {
+		val = math.Ldexp(float64(mant|1<<f.mantBits), int(exp)-f.bias()-f.mantBits)
+	}
+	if sign != 0 {
+		val = -val
+	}
+	return float32(val)
+}
+
+// encode converts val to a code of the format, rounding to nearest with ties to even.
+// Values too large for the format, and infinities in formats without them, are converted as described by overflow.
+// The top bits of the payload of NaNs are kept, and a NaN is never converted to an infinity.
+func (f minifloat) encode(val float32, saturate bool) uint64 {
+	in := math.Float32bits(val)
+	sign := uint64(in>>31) << (f.width() - 1)
+	exp32 := int(in>>23) & 0xFF
+	mant32 := uint64(in & 0x7FFFFF)
+
+	if exp32 == 0xFF {
+		if mant32 != 0 {
+			if f.finite {
+				return sign | (f.maxFinite() + 1)
+			}
+			payload := mant32 >> (23 - f.mantBits)
+			if payload == 0 {
+				payload = 1 << (f.mantBits - 1)
+			}
+			return sign | f.expMax()<<f.mantBits | payload
+		}
+		return f.overflow(sign, saturate)
+	}
+	if exp32 == 0 && mant32 == 0 {
+		return sign
+	}
+
+	// The value is significand * 2^(exp-23), and its unbiased exponent is unbiasedExp.
+	significand, exp := mant32|1<<23, exp32-127
+	if exp32 == 0 {
+		significand, exp = mant32, -126
+	}
+	unbiasedExp := exp - 23 + bits.Len64(significand) - 1
+
+	minExp := 1 - f.bias()
+	if unbiasedExp < minExp {
+		unbiasedExp = minExp
+	}
+	shift := unbiasedExp - f.mantBits - (exp - 23)
+	var quantized uint64
+	switch {
+	case shift <= 0:
+		quantized = significand << -shift
+	case shift > 25:
+		quantized = 0
+	default:
+		quantized = significand >> shift
+		rem := significand & (1<<shift - 1)
+		half := uint64(1) << (shift - 1)
+		if rem > half || (rem == half && quantized&1 == 1) {
+			quantized++
+		}
+	}
+	// Subnormal codes are the quantized value itself, and a carry out of the mantissa moves to the next exponent.
+	code := uint64(unbiasedExp+f.bias()-1)<<f.mantBits + quantized
+	if code > f.maxFinite() {
+		return f.overflow(sign, saturate)
+	}
+	return sign | code
+}
+
+func (format FP8Format) minifloat() (minifloat, error) {
+	switch format {
+	case E4M3:
+		return e4m3Format, nil
+	case E5M2:
+		return e5m2Format, nil
+	}
+	return minifloat{}, errors.Wrapf(UnexpectedCondition, "invalid FP8 format: %d", format)
+}
+
+// GetFloat16FieldFromSlice extracts an IEEE 754 half precision field from a slice of uint64 and returns it as a float32.
+func GetFloat16FieldFromSlice(inputFieldSlice []uint64, offsetInBits uint64) (float32, error) {
+	return getMinifloatFieldFromSlice(inputFieldSlice, float16Format, offsetInBits)
+}
+
+// SetFloat16FieldToSlice sets an IEEE 754 half precision field in a slice of uint64, rounding val to nearest even.
+func SetFloat16FieldToSlice(destinationField []uint64, val float32, offsetInBits uint64) ([]uint64, error) {
+	return Set64BitsFieldToSlice(destinationField, float16Format.encode(val, false), 16, offsetInBits)
+}
+
+// GetBFloat16FieldFromSlice extracts a bfloat16 field from a slice of uint64 and returns it as a float32.
+func GetBFloat16FieldFromSlice(inputFieldSlice []uint64, offsetInBits uint64) (float32, error) {
+	return getMinifloatFieldFromSlice(inputFieldSlice, bfloat16Format, offsetInBits)
+}
+
+// SetBFloat16FieldToSlice sets a bfloat16 field in a slice of uint64, rounding val to nearest even.
+func SetBFloat16FieldToSlice(destinationField []uint64, val float32, offsetInBits uint64) ([]uint64, error) {
+	return Set64BitsFieldToSlice(destinationField, bfloat16Format.encode(val, false), 16, offsetInBits)
+}
+
+// GetFP8FieldFromSlice extracts an 8 bits floating point field of the given format from a slice of uint64
+// and returns it as a float32.
+func GetFP8FieldFromSlice(inputFieldSlice []uint64, format FP8Format, offsetInBits uint64) (float32, error) {
+	f, err := format.minifloat()
+	if err != nil {
+		return 0, err
+	}
+	return getMinifloatFieldFromSlice(inputFieldSlice, f, offsetInBits)
+}
+
+// SetFP8FieldToSlice sets an 8 bits floating point field of the given format in a slice of uint64, rounding val
+// to nearest even. Values out of range are converted to the largest finite value of the same sign when saturate
+// is set, and otherwise to an infinity for E5M2 and to a NaN for E4M3, which has no infinities.
+func SetFP8FieldToSlice(destinationField []uint64, val float32, format FP8Format, saturate bool, offsetInBits uint64) ([]uint64, error) {
+	f, err := format.minifloat()
+	if err != nil {
+		return nil, err
+	}
+	return Set64BitsFieldToSlice(destinationField, f.encode(val, saturate), 8, offsetInBits)
+}
+
+// GetFloat32FieldFromSlice extracts an IEEE 754 single precision field from a slice of uint64.
+func GetFloat32FieldFromSlice(inputFieldSlice []uint64, offsetInBits uint64) (float32, error) {
+	field, err := Get64BitsFieldFromSlice(inputFieldSlice, 32, offsetInBits)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(uint32(field)), nil
+}
+
+// SetFloat32FieldToSlice sets an IEEE 754 single precision field in a slice of uint64.
+func SetFloat32FieldToSlice(destinationField []uint64, val float32, offsetInBits uint64) ([]uint64, error) {
+	return Set64BitsFieldToSlice(destinationField, uint64(math.Float32bits(val)), 32, offsetInBits)
+}
+
+// GetFloat64FieldFromSlice extracts an IEEE 754 double precision field from a slice of uint64.
+func GetFloat64FieldFromSlice(inputFieldSlice []uint64, offsetInBits uint64) (float64, error) {
+	field, err := Get64BitsFieldFromSlice(inputFieldSlice, 64, offsetInBits)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(field), nil
+}
+
+// SetFloat64FieldToSlice sets an IEEE 754 double precision field in a slice of uint64.
+func SetFloat64FieldToSlice(destinationField []uint64, val float64, offsetInBits uint64) ([]uint64, error) {
+	return Set64BitsFieldToSlice(destinationField, math.Float64bits(val), 64, offsetInBits)
+}
+
+func getMinifloatFieldFromSlice(inputFieldSlice []uint64, f minifloat, offsetInBits uint64) (float32, error) {
+	field, err := Get64BitsFieldFromSlice(inputFieldSlice, uint64(f.width()), offsetInBits)
+	if err != nil {
+		return 0, err
+	}
+	return f.decode(field), nil
+}
+
+// ReadFloat16 reads an IEEE 754 half precision value from the bit stream and returns it as a float32.
+func (wr *Reader) ReadFloat16() (float32, error) {
+	return wr.readMinifloat(float16Format)
+}
+
+// ReadBFloat16 reads a bfloat16 value from the bit stream and returns it as a float32.
+func (wr *Reader) ReadBFloat16() (float32, error) {
+	return wr.readMinifloat(bfloat16Format)
+}
+
+// ReadFP8 reads an 8 bits floating point value of the given format from the bit stream and returns it as a float32.
+func (wr *Reader) ReadFP8(format FP8Format) (float32, error) {
+	f, err := format.minifloat()
+	if err != nil {
+		return 0, err
+	}
+	return wr.readMinifloat(f)
+}
+
+// ReadFloat32 reads an IEEE 754 single precision value from the bit stream.
+func (wr *Reader) ReadFloat32() (float32, error) {
+	val, err := wr.ReadNbitsUint64(32)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(uint32(val)), nil
+}
+
+// ReadFloat64 reads an IEEE 754 double precision value from the bit stream.
+func (wr *Reader) ReadFloat64() (float64, error) {
+	val, err := wr.ReadNbitsUint64(64)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(val), nil
+}
+
+func (wr *Reader) readMinifloat(f minifloat) (float32, error) {
+	val, err := wr.ReadNbitsUint64(f.width())
+	if err != nil {
+		return 0, err
+	}
+	return f.decode(val), nil
+}
+
+// WriteFloat16 writes val as an IEEE 754 half precision value, rounding it to nearest even.
+// Values out of range are written as infinities.
+func (wr *Writer) WriteFloat16(val float32) error {
+	return wr.WriteNbitsFromWord(16, float16Format.encode(val, false))
+}
+
+// WriteBFloat16 writes val as a bfloat16 value, rounding it to nearest even.
+// Values out of range are written as infinities.
+func (wr *Writer) WriteBFloat16(val float32) error {
+	return wr.WriteNbitsFromWord(16, bfloat16Format.encode(val, false))
+}
+
+// WriteFP8 writes val as an 8 bits floating point value of the given format, rounding it to nearest even.
+// Values out of range, and infinities, are written as the largest finite value of the same sign when saturate is set.
+// Otherwise they are written as an infinity for E5M2 and as a NaN for E4M3, which has no infinities.
+func (wr *Writer) WriteFP8(format FP8Format, val float32, saturate bool) error {
+	f, err := format.minifloat()
+	if err != nil {
+		return err
+	}
+	return wr.WriteNbitsFromWord(8, f.encode(val, saturate))
+}
+
+// WriteFloat32 writes val as an IEEE 754 single precision value.
+func (wr *Writer) WriteFloat32(val float32) error {
+	return wr.WriteNbitsFromWord(32, uint64(math.Float32bits(val)))
+}
+
+// WriteFloat64 writes val as an IEEE 754 double precision value.
+func (wr *Writer) WriteFloat64(val float64) error {
+	return wr.WriteNbitsFromWord(64, math.Float64bits(val))
+}
@@ -0,0 +1,223 @@
+package gobitstream_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestMinifloatRoundTripAllCodes(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name  string
+		width uint64
+		get   func([]uint64) (float32, error)
+		set   func([]uint64, float32) ([]uint64, error)
+	}{
+		{
+			name:  "float16",
+			width: 16,
+			get:   func(s []uint64) (float32, error) { return gobitstream.GetFloat16FieldFromSlice(s, 5) },
+			set:   func(s []uint64, v float32) ([]uint64, error) { return gobitstream.SetFloat16FieldToSlice(s, v, 5) },
+		},
+		{
+			name:  "bfloat16",
+			width: 16,
+			get:   func(s []uint64) (float32, error) { return gobitstream.GetBFloat16FieldFromSlice(s, 5) },
+			set:   func(s []uint64, v float32) ([]uint64, error) { return gobitstream.SetBFloat16FieldToSlice(s, v, 5) },
+		},
+		{
+			name:  "E4M3",
+			width: 8,
+			get: func(s []uint64) (float32, error) {
+				return gobitstream.GetFP8FieldFromSlice(s, gobitstream.E4M3, 5)
+			},
+			set: func(s []uint64, v float32) ([]uint64, error) {
+				return gobitstream.SetFP8FieldToSlice(s, v, gobitstream.E4M3, false, 5)
+			},
+		},
+		{
+			name:  "E5M2",
+			width: 8,
+			get: func(s []uint64) (float32, error) {
+				return gobitstream.GetFP8FieldFromSlice(s, gobitstream.E5M2, 5)
+			},
+			set: func(s []uint64, v float32) ([]uint64, error) {
+				return gobitstream.SetFP8FieldToSlice(s, v, gobitstream.E5M2, false, 5)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for code := uint64(0); code < 1<<tc.width; code++ {
+				val, err := tc.get([]uint64{code << 5})
+				a.Nil(err)
+				dst, err := tc.set([]uint64{0}, val)
+				a.Nil(err)
+				if !a.Equal(code<<5, dst[0], "code: %#x, value: %v", code, val) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestFloat16Encoding(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		val      float32
+		expected uint64
+	}{
+		{name: "one", val: 1, expected: 0x3C00},
+		{name: "minus two", val: -2, expected: 0xC000},
+		{name: "negative zero", val: float32(math.Copysign(0, -1)), expected: 0x8000},
+		{name: "max", val: 65504, expected: 0x7BFF},
+		{name: "rounds to max", val: 65519, expected: 0x7BFF},
+		{name: "overflow tie to even", val: 65520, expected: 0x7C00},
+		{name: "infinity", val: float32(math.Inf(-1)), expected: 0xFC00},
+		{name: "min subnormal", val: float32(math.Ldexp(1, -24)), expected: 0x0001},
+		{name: "underflow tie to even", val: float32(math.Ldexp(1, -25)), expected: 0x0000},
+		{name: "underflow rounds up", val: float32(math.Ldexp(1.5, -25)), expected: 0x0001},
+		{name: "largest subnormal", val: float32(math.Ldexp(1023, -24)), expected: 0x03FF},
+		{name: "subnormal carry to normal", val: float32(math.Ldexp(2047, -25)), expected: 0x0400},
+		{name: "float32 subnormal", val: math.Float32frombits(1), expected: 0x0000},
+		{name: "quiet NaN payload", val: math.Float32frombits(0x7FC02000), expected: 0x7E01},
+		{name: "signaling NaN payload", val: math.Float32frombits(0x7FA00000), expected: 0x7D00},
+		{name: "NaN payload below precision", val: math.Float32frombits(0xFF800001), expected: 0xFE00},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst, err := gobitstream.SetFloat16FieldToSlice([]uint64{0}, tc.val, 0)
+			a.Nil(err)
+			a.Equal(tc.expected, dst[0])
+		})
+	}
+
+	val, err := gobitstream.GetFloat16FieldFromSlice([]uint64{0x7D00}, 0)
+	a.Nil(err)
+	a.Equal(uint32(0x7FA00000), math.Float32bits(val))
+}
+
+func TestBFloat16Encoding(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		val      float32
+		expected uint64
+	}{
+		{name: "one", val: 1, expected: 0x3F80},
+		{name: "pi", val: math.Pi, expected: 0x4049},
+		{name: "tie to even down", val: math.Float32frombits(0x3F808000), expected: 0x3F80},
+		{name: "tie to even up", val: math.Float32frombits(0x3F818000), expected: 0x3F82},
+		{name: "overflow", val: math.MaxFloat32, expected: 0x7F80},
+		{name: "min subnormal", val: math.Float32frombits(0x00010000), expected: 0x0001},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst, err := gobitstream.SetBFloat16FieldToSlice([]uint64{0}, tc.val, 0)
+			a.Nil(err)
+			a.Equal(tc.expected, dst[0])
+		})
+	}
+}
+
+func TestFP8Encoding(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	inf := float32(math.Inf(1))
+	nan := float32(math.NaN())
+	testCases := []struct {
+		name     string
+		format   gobitstream.FP8Format
+		val      float32
+		saturate bool
+		expected uint64
+	}{
+		{name: "E4M3 one", format: gobitstream.E4M3, val: 1, expected: 0x38},
+		{name: "E4M3 max", format: gobitstream.E4M3, val: 448, expected: 0x7E},
+		{name: "E4M3 tie to max", format: gobitstream.E4M3, val: 464, expected: 0x7E},
+		{name: "E4M3 overflow", format: gobitstream.E4M3, val: 480, expected: 0x7F},
+		{name: "E4M3 overflow saturated", format: gobitstream.E4M3, val: 480, saturate: true, expected: 0x7E},
+		{name: "E4M3 negative saturated", format: gobitstream.E4M3, val: -1000, saturate: true, expected: 0xFE},
+		{name: "E4M3 infinity", format: gobitstream.E4M3, val: inf, expected: 0x7F},
+		{name: "E4M3 infinity saturated", format: gobitstream.E4M3, val: -inf, saturate: true, expected: 0xFE},
+		{name: "E4M3 NaN", format: gobitstream.E4M3, val: nan, saturate: true, expected: 0x7F},
+		{name: "E4M3 min subnormal", format: gobitstream.E4M3, val: float32(math.Ldexp(1, -9)), expected: 0x01},
+		{name: "E5M2 one", format: gobitstream.E5M2, val: 1, expected: 0x3C},
+		{name: "E5M2 max", format: gobitstream.E5M2, val: 57344, expected: 0x7B},
+		{name: "E5M2 overflow", format: gobitstream.E5M2, val: 61440, expected: 0x7C},
+		{name: "E5M2 overflow saturated", format: gobitstream.E5M2, val: 61440, saturate: true, expected: 0x7B},
+		{name: "E5M2 infinity", format: gobitstream.E5M2, val: -inf, expected: 0xFC},
+		{name: "E5M2 infinity saturated", format: gobitstream.E5M2, val: inf, saturate: true, expected: 0x7B},
+		{name: "E5M2 NaN", format: gobitstream.E5M2, val: nan, saturate: true, expected: 0x7E},
+		{name: "E5M2 min subnormal", format: gobitstream.E5M2, val: float32(math.Ldexp(1, -16)), expected: 0x01},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst, err := gobitstream.SetFP8FieldToSlice([]uint64{0}, tc.val, tc.format, tc.saturate, 0)
+			a.Nil(err)
+			a.Equal(tc.expected, dst[0])
+		})
+	}
+
+	_, err := gobitstream.SetFP8FieldToSlice([]uint64{0}, 1, gobitstream.FP8Format(2), false, 0)
+	a.NotNil(err)
+	_, err = gobitstream.GetFP8FieldFromSlice([]uint64{0}, gobitstream.FP8Format(-1), 0)
+	a.NotNil(err)
+}
+
+func TestReadWriteFloats(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+		w, err := gobitstream.NewWriterWithOrder(3+16+16+8+8+32+64+5, gobitstream.BigEndian, bitOrder)
+		a.Nil(err)
+		a.Nil(w.WriteNbitsFromWord(3, 0x5))
+		a.Nil(w.WriteFloat16(-1.5))
+		a.Nil(w.WriteBFloat16(3.140625))
+		a.Nil(w.WriteFP8(gobitstream.E4M3, 0.5, false))
+		a.Nil(w.WriteFP8(gobitstream.E5M2, 1e6, true))
+		a.Nil(w.WriteFloat32(math.Float32frombits(0x7FA00001)))
+		a.Nil(w.WriteFloat64(math.Pi))
+		a.Nil(w.WriteNbitsFromWord(5, 0x1F))
+		a.NotNil(w.WriteFP8(gobitstream.FP8Format(3), 1, false))
+
+		rd := w.Reader()
+		v, err := rd.ReadNbitsUint64(3)
+		a.Nil(err)
+		a.Equal(uint64(0x5), v)
+		f, err := rd.ReadFloat16()
+		a.Nil(err)
+		a.Equal(float32(-1.5), f)
+		f, err = rd.ReadBFloat16()
+		a.Nil(err)
+		a.Equal(float32(3.140625), f)
+		f, err = rd.ReadFP8(gobitstream.E4M3)
+		a.Nil(err)
+		a.Equal(float32(0.5), f)
+		f, err = rd.ReadFP8(gobitstream.E5M2)
+		a.Nil(err)
+		a.Equal(float32(57344), f)
+		f, err = rd.ReadFloat32()
+		a.Nil(err)
+		a.Equal(uint32(0x7FA00001), math.Float32bits(f))
+		d, err := rd.ReadFloat64()
+		a.Nil(err)
+		a.Equal(math.Pi, d)
+		v, err = rd.ReadNbitsUint64(5)
+		a.Nil(err)
+		a.Equal(uint64(0x1F), v)
+		_, err = rd.ReadFloat16()
+		a.NotNil(err)
+	}
+}
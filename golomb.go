@@ -0,0 +1,181 @@
+package gobitstream
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// Unary, Rice and Golomb codes, as used by FLAC residuals and similar entropy coders.
+//
+// Rice and Golomb codes write the quotient in unary as zero bits terminated by a one bit, followed by the remainder.
+
+// checkStopBit validates the terminating bit value of a unary code.
+func checkStopBit(stopBit uint64) error {
+	if stopBit > 1 {
+		return errors.Wrapf(InvalidValueSizeError, "stop bit must be 0 or 1, got: %d", stopBit)
+	}
+	return nil
+}
+
+// checkRiceParameter validates the parameter of a Rice code.
+func checkRiceParameter(k int) error {
+	if k < 0 || k > 63 {
+		return errors.Wrapf(InvalidWidthError, "rice parameter must be between 0 and 63, got: %d", k)
+	}
+	return nil
+}
+
+// ReadUnary reads a unary coded value: the number of bits different from stopBit found before stopBit.
+// The terminating stopBit is consumed too. A TruncatedCodeError is returned, and nothing is consumed,
+// if the bit stream ends before stopBit is found.
+func (wr *Reader) ReadUnary(stopBit uint64) (uint64, error) {
+	if err := checkStopBit(stopBit); err != nil {
+		return 0, err
+	}
+	n, terminated := wr.runLength(stopBit ^ 1)
+	if !terminated {
+		return 0, errors.Wrapf(TruncatedCodeError, "unary code at offset: %d", wr.offset)
+	}
+	wr.offset += n + 1
+	return uint64(n), nil
+}
+
+// WriteUnary writes val as a unary code: val bits different from stopBit followed by stopBit.
+func (wr *Writer) WriteUnary(val uint64, stopBit uint64) error {
+	if err := checkStopBit(stopBit); err != nil {
+		return err
+	}
+	if val > math.MaxInt32 {
+		return errors.Wrapf(InvalidValueSizeError, "unary value too large: %d", val)
+	}
+	start := wr.offset
+	err := wr.writeRun(stopBit^1, int(val))
+	if err == nil {
+		err = wr.writeNbits(1, stopBit)
+	}
+	if err != nil {
+		wr.offset = start
+		return err
+	}
+	return nil
+}
+
+// ReadRice reads a Rice coded value with parameter k: a unary quotient followed by a k bits remainder.
+// k must be between 0 and 63. A CodeOverflowError is returned if the value does not fit in 64 bits.
+// On error nothing is consumed.
+func (wr *Reader) ReadRice(k int) (uint64, error) {
+	if err := checkRiceParameter(k); err != nil {
+		return 0, err
+	}
+	start := wr.offset
+	q, err := wr.ReadUnary(1)
+	if err != nil {
+		return 0, err
+	}
+	if q > math.MaxUint64>>k {
+		wr.offset = start
+		return 0, errors.Wrapf(CodeOverflowError, "rice code at offset: %d", start)
+	}
+	var r uint64
+	if k > 0 {
+		if r, err = wr.ReadNbitsUint64(k); err != nil {
+			wr.offset = start
+			return 0, errors.Wrapf(TruncatedCodeError, "rice remainder at offset: %d", start)
+		}
+	}
+	return q<<k | r, nil
+}
+
+// WriteRice writes val as a Rice code with parameter k. k must be between 0 and 63.
+func (wr *Writer) WriteRice(k int, val uint64) error {
+	if err := checkRiceParameter(k); err != nil {
+		return err
+	}
+	start := wr.offset
+	err := wr.WriteUnary(val>>k, 1)
+	if err == nil && k > 0 {
+		err = wr.writeNbits(k, val)
+	}
+	if err != nil {
+		wr.offset = start
+		return err
+	}
+	return nil
+}
+
+// golombParameters returns the truncated binary parameters of a Golomb code with divisor m:
+// the remainder is written with b-1 bits when it is below cutoff, and with b bits otherwise.
+func golombParameters(m uint64) (b int, cutoff uint64) {
+	b = bits.Len64(m - 1)
+	return b, uint64(1)<<b - m
+}
+
+// ReadGolomb reads a Golomb coded value with divisor m: a unary quotient followed by a truncated binary remainder.
+// The remainder is read most significant bit first, so its first b-1 bits are read as one field and the
+// optional last bit as another. When m is a power of two the code is the Rice code of ReadRice.
+// A CodeOverflowError is returned if the value does not fit in 64 bits. On error nothing is consumed.
+func (wr *Reader) ReadGolomb(m uint64) (uint64, error) {
+	if m == 0 {
+		return 0, errors.Wrap(InvalidValueSizeError, "golomb divisor cannot be 0")
+	}
+	if m&(m-1) == 0 {
+		return wr.ReadRice(bits.TrailingZeros64(m))
+	}
+
+	start := wr.offset
+	q, err := wr.ReadUnary(1)
+	if err != nil {
+		return 0, err
+	}
+
+	b, cutoff := golombParameters(m)
+	r, err := wr.ReadNbitsUint64(b - 1)
+	if err == nil && r >= cutoff {
+		var low uint64
+		low, err = wr.ReadNbitsUint64(1)
+		r = (r<<1 | low) - cutoff
+	}
+	if err != nil {
+		wr.offset = start
+		return 0, errors.Wrapf(TruncatedCodeError, "golomb remainder at offset: %d", start)
+	}
+
+	hi, lo := bits.Mul64(q, m)
+	val, carry := bits.Add64(lo, r, 0)
+	if hi != 0 || carry != 0 {
+		wr.offset = start
+		return 0, errors.Wrapf(CodeOverflowError, "golomb code at offset: %d", start)
+	}
+	return val, nil
+}
+
+// WriteGolomb writes val as a Golomb code with divisor m, with the layout described in ReadGolomb.
+func (wr *Writer) WriteGolomb(m uint64, val uint64) error {
+	if m == 0 {
+		return errors.Wrap(InvalidValueSizeError, "golomb divisor cannot be 0")
+	}
+	if m&(m-1) == 0 {
+		return wr.WriteRice(bits.TrailingZeros64(m), val)
+	}
+
+	start := wr.offset
+	err := wr.WriteUnary(val/m, 1)
+	if err == nil {
+		b, cutoff := golombParameters(m)
+		if r := val % m; r < cutoff {
+			err = wr.writeNbits(b-1, r)
+		} else {
+			r += cutoff
+			if err = wr.writeNbits(b-1, r>>1); err == nil {
+				err = wr.writeNbits(1, r)
+			}
+		}
+	}
+	if err != nil {
+		wr.offset = start
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package gobitstream_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+	"github.com/pkg/errors"
+)
+
+func TestUnary(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewWriterLE(256)
+	a.Nil(w.WriteUnary(3, 1))
+	a.Nil(w.WriteUnary(2, 0))
+	a.Nil(w.WriteUnary(0, 1))
+	a.Equal(uint64(0xB8), w.Uint64())
+
+	a.Nil(w.WriteUnary(130, 1))
+	a.NotNil(w.WriteUnary(1, 2))
+	a.Nil(w.Flush())
+
+	rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+	a.Nil(err)
+	for _, tc := range []struct{ stop, expected uint64 }{{1, 3}, {0, 2}, {1, 0}, {1, 130}} {
+		v, err := rd.ReadUnary(tc.stop)
+		a.Nil(err)
+		a.Equal(tc.expected, v)
+	}
+	a.Equal(139, rd.Offset())
+
+	_, err = rd.ReadUnary(1)
+	a.Equal(gobitstream.TruncatedCodeError, errors.Cause(err))
+	a.Equal(139, rd.Offset())
+}
+
+func TestRiceLayout(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	// 11 with k = 2: quotient 2 as 001, remainder 3 as 11.
+	w := gobitstream.NewWriterLE(64)
+	a.Nil(w.WriteRice(2, 11))
+	a.Equal(uint64(0x1C), w.Uint64())
+
+	a.Nil(w.Flush())
+	rd, err := gobitstream.NewReaderLE(8, w.Bytes())
+	a.Nil(err)
+	v, err := rd.ReadRice(2)
+	a.Nil(err)
+	a.Equal(uint64(11), v)
+	a.Equal(5, rd.Offset())
+}
+
+func TestGolombLayout(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	// m = 5: b = 3, cutoff = 3. Remainders 0..2 use 2 bits, 3 and 4 use 3 bits.
+	testCases := []struct {
+		val      uint64
+		bits     int
+		expected uint64
+	}{
+		{val: 0, bits: 3, expected: 0x1},
+		{val: 2, bits: 3, expected: 0x5},
+		{val: 3, bits: 4, expected: 0x7},
+		{val: 4, bits: 4, expected: 0xF},
+		{val: 9, bits: 5, expected: 0x1E},
+	}
+
+	for _, tc := range testCases {
+		w := gobitstream.NewWriterLE(64)
+		a.Nil(w.WriteGolomb(5, tc.val))
+		a.Equal(tc.expected, w.Uint64(), "val: %d", tc.val)
+
+		a.Nil(w.Flush())
+		rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+		a.Nil(err)
+		v, err := rd.ReadGolomb(5)
+		a.Nil(err)
+		a.Equal(tc.val, v)
+		a.Equal(tc.bits, rd.Offset())
+	}
+}
+
+func TestRiceGolombRoundTrip(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	values := make([]uint64, 500)
+	for i := range values {
+		values[i] = uint64(r.Intn(5000))
+	}
+
+	for _, m := range []uint64{1, 2, 3, 7, 10, 64, 100, 1000} {
+		w := gobitstream.NewGrowableWriterLE()
+		for _, v := range values {
+			a.Nil(w.WriteGolomb(m, v))
+		}
+		a.Nil(w.Flush())
+
+		rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+		a.Nil(err)
+		for _, expected := range values {
+			v, err := rd.ReadGolomb(m)
+			a.Nil(err)
+			a.Equal(expected, v, "m: %d", m)
+		}
+	}
+
+	for _, k := range []int{0, 1, 5, 12, 63} {
+		w := gobitstream.NewGrowableWriterLE()
+		for _, v := range values {
+			a.Nil(w.WriteRice(k, v<<k>>k))
+		}
+		a.Nil(w.WriteRice(k, math.MaxUint64>>(64-k)<<k>>k))
+		a.Nil(w.Flush())
+
+		rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+		a.Nil(err)
+		for _, expected := range values {
+			v, err := rd.ReadRice(k)
+			a.Nil(err)
+			a.Equal(expected<<k>>k, v, "k: %d", k)
+		}
+	}
+}
+
+func TestRiceGolombErrors(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewGrowableWriterLE()
+	a.NotNil(w.WriteRice(64, 0))
+	a.NotNil(w.WriteGolomb(0, 0))
+
+	// A quotient of 2 does not fit with k = 63.
+	a.Nil(w.WriteUnary(2, 1))
+	a.Nil(w.WriteNbitsFromWord(63, 0))
+	a.Nil(w.Flush())
+	rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+	a.Nil(err)
+	_, err = rd.ReadRice(63)
+	a.Equal(gobitstream.CodeOverflowError, errors.Cause(err))
+	a.Equal(0, rd.Offset())
+
+	// The remainder is cut by the end of the bit stream.
+	rd, err = gobitstream.NewReaderLE(3, []byte{0x01})
+	a.Nil(err)
+	_, err = rd.ReadRice(4)
+	a.Equal(gobitstream.TruncatedCodeError, errors.Cause(err))
+	_, err = rd.ReadGolomb(100)
+	a.Equal(gobitstream.TruncatedCodeError, errors.Cause(err))
+	a.Equal(0, rd.Offset())
+	_, err = rd.ReadGolomb(0)
+	a.NotNil(err)
+}
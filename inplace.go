@@ -0,0 +1,180 @@
+package gobitstream
+
+import (
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// NewWriterOn creates a Writer that writes its fields directly into dst, starting bitOffset bits into it and
+// limited to sizeInBits bits, so a region of a larger preallocated buffer can be serialized without copying.
+// The bits of dst outside the region written are left untouched, and no Flush is needed for them to be in dst.
+// With LittleEndian byte order the bit stream starts at the first byte of the region. With BigEndian it starts at
+// the last byte of the BitsToBytesSize(sizeInBits) bytes of the region, which then hold once full what a Writer
+// created with NewWriterBE(sizeInBits) produces, and bitOffset must be a multiple of 8.
+// bitOrder chooses which bit of each byte comes first.
+// Bits of the region past the offset may be modified by a write that fails.
+// It returns an error if the region does not fit in dst.
+func NewWriterOn(dst []byte, bitOffset, sizeInBits int, byteOrder ByteOrder, bitOrder BitOrder) (*Writer, error) {
+	if err := checkOrders(byteOrder, bitOrder); err != nil {
+		return nil, err
+	}
+	if bitOffset < 0 || sizeInBits < 0 || bitOffset+sizeInBits > len(dst)*8 {
+		return nil, errors.Wrapf(OffsetOutOfRangeError, "bitOffset: %d, sizeInBits: %d, buffer: %d bits", bitOffset, sizeInBits, len(dst)*8)
+	}
+	if byteOrder == BigEndian {
+		if bitOffset%8 != 0 {
+			return nil, errors.Wrapf(InvalidOffsetError, "big-endian in-place region must start on a byte boundary, bitOffset: %d", bitOffset)
+		}
+		// The region is cut to its bytes, so the first bit of the stream is found from its last byte.
+		start := bitOffset / 8
+		dst, bitOffset = dst[start:start+BitsToBytesSize(sizeInBits)], 0
+	}
+	return &Writer{
+		size:           sizeInBits,
+		sizeInWords:    bitsToWordSize(sizeInBits),
+		sizeInBytes:    BitsToBytesSize(sizeInBits),
+		isLittleEndian: byteOrder == LittleEndian,
+		bitOrder:       bitOrder,
+		inPlace:        true,
+		on:             dst,
+		onOffset:       bitOffset,
+	}, nil
+}
+
+// setBitsInBytes stores the nBits low bits of val, up to 64, in b starting at bitOffset, keeping the other bits of b.
+// With msbFirst the bits of each byte are counted from its most significant bit, and with reversed the bytes are
+// counted from the last byte of b.
+func setBitsInBytes(b []byte, bitOffset, nBits int, val uint64, msbFirst, reversed bool) {
+	for nBits > 0 {
+		index, shift := bitOffset/8, bitOffset%8
+		if reversed {
+			index = len(b) - 1 - index
+		}
+		n := 8 - shift
+		if n > nBits {
+			n = nBits
+		}
+		mask := (byte(1)<<n - 1) << shift
+		field := byte(val<<shift) & mask
+		if msbFirst {
+			mask, field = bits.Reverse8(mask), bits.Reverse8(field)
+		}
+		b[index] = b[index]&^mask | field
+		val >>= n
+		bitOffset += n
+		nBits -= n
+	}
+}
+
+// getBitsFromBytes returns the nBits bits, up to 64, of b starting at bitOffset, counted as setBitsInBytes does.
+func getBitsFromBytes(b []byte, bitOffset, nBits int, msbFirst, reversed bool) (val uint64) {
+	for read := 0; read < nBits; {
+		index, shift := (bitOffset+read)/8, (bitOffset+read)%8
+		if reversed {
+			index = len(b) - 1 - index
+		}
+		n := 8 - shift
+		if n > nBits-read {
+			n = nBits - read
+		}
+		field := b[index]
+		if msbFirst {
+			field = bits.Reverse8(field)
+		}
+		val |= uint64(field>>shift&(byte(1)<<n-1)) << read
+		read += n
+	}
+	return val
+}
+
+// setField stores the nBits low bits of val, up to 64, at offsetInBits of the bit stream: in the backing words,
+// or in the caller's bytes for a Writer created with NewWriterOn. Every write of the Writer goes through it.
+func (wr *Writer) setField(offsetInBits, nBits int, val uint64) error {
+	if wr.inPlace {
+		if nBits <= 0 || nBits > 64 {
+			return errors.Wrapf(InvalidBitsSizeError, "nBits: %d", nBits)
+		}
+		if offsetInBits+nBits > wr.size {
+			return errors.Wrapf(OffsetOutOfRangeError, "offset: %d, nBits: %d, size: %d", offsetInBits, nBits, wr.size)
+		}
+		setBitsInBytes(wr.on, wr.onOffset+offsetInBits, nBits, val, wr.bitOrder == MSBFirst, !wr.isLittleEndian)
+		wr.markCoverage(offsetInBits, nBits, true)
+		return nil
+	}
+
+	var dst []uint64
+	var err error
+	if offsetInBits >= 64 {
+		dst, err = SetFieldToSlice(wr.dstWord, []uint64{val}, uint64(nBits), uint64(offsetInBits))
+	} else {
+		dst, err = Set64BitsFieldToSlice(wr.dstWord, val, uint64(nBits), uint64(offsetInBits))
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	wr.dstWord = dst
+	wr.markCoverage(offsetInBits, nBits, true)
+	return nil
+}
+
+// setFieldWords stores the nBits low bits of words, least significant word first, at offsetInBits of the bit stream.
+func (wr *Writer) setFieldWords(offsetInBits, nBits int, words []uint64) error {
+	if !wr.inPlace {
+		dst, err := SetFieldToSlice(wr.dstWord, words, uint64(nBits), uint64(offsetInBits))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		wr.dstWord = dst
+		wr.markCoverage(offsetInBits, nBits, true)
+		return nil
+	}
+	if offsetInBits+nBits > wr.size {
+		return errors.Wrapf(OffsetOutOfRangeError, "offset: %d, nBits: %d, size: %d", offsetInBits, nBits, wr.size)
+	}
+	for i := 0; nBits > 0; i++ {
+		width := nBits
+		if width > 64 {
+			width = 64
+		}
+		if err := wr.setField(offsetInBits+64*i, width, words[i]); err != nil {
+			return err
+		}
+		nBits -= width
+	}
+	return nil
+}
+
+// getField returns the nBits bits, up to 64, at offsetInBits of the bit stream, as they are stored by setField.
+func (wr *Writer) getField(offsetInBits, nBits int) (uint64, error) {
+	if wr.inPlace {
+		return getBitsFromBytes(wr.on, wr.onOffset+offsetInBits, nBits, wr.bitOrder == MSBFirst, !wr.isLittleEndian), nil
+	}
+	value, err := Get64BitsFieldFromSlice(wr.dstWord, uint64(nBits), uint64(offsetInBits))
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return value, nil
+}
+
+// loadWords copies the bits written so far in the caller's bytes to the backing words, for the methods that
+// read them, when the Writer was created with NewWriterOn. The backing words are sized for the whole region,
+// as the ones of a Writer of fixed size, and the bits past the offset are zero.
+func (wr *Writer) loadWords() {
+	if !wr.inPlace {
+		return
+	}
+	if len(wr.dstWord) != wr.sizeInWords {
+		wr.dstWord = make([]uint64, wr.sizeInWords)
+	}
+	for i := range wr.dstWord {
+		width := wr.offset - 64*i
+		if width > 64 {
+			width = 64
+		}
+		wr.dstWord[i] = 0
+		if width > 0 {
+			wr.dstWord[i] = getBitsFromBytes(wr.on, wr.onOffset+64*i, width, wr.bitOrder == MSBFirst, !wr.isLittleEndian)
+		}
+	}
+}
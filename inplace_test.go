@@ -0,0 +1,167 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+// bitAt returns the bit at pos of b, counting the bits of each byte from the most significant one if msbFirst.
+func bitAt(b []byte, pos int, msbFirst bool) byte {
+	shift := pos % 8
+	if msbFirst {
+		shift = 7 - shift
+	}
+	return b[pos/8] >> shift & 1
+}
+
+// writeFields writes a mix of fields covering the paths of the Writer, 118 bits in total.
+func writeFields(w *gobitstream.Writer) error {
+	if err := w.WriteNbitsFromWord(5, 0x15); err != nil {
+		return err
+	}
+	r, err := w.Reserve(11)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteNbitsFromBytes(70, []byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0, 0x3F}); err != nil {
+		return err
+	}
+	if err := w.WriteUE(37); err != nil {
+		return err
+	}
+	if err := w.WriteNbitsFromInt64(21, -12345); err != nil {
+		return err
+	}
+	return r.Set(0x5A5)
+}
+
+func TestNewWriterOn(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	const size = 118
+	for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+		for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+			for _, bitOffset := range []int{0, 3, 8, 61} {
+				if byteOrder == gobitstream.BigEndian && bitOffset%8 != 0 {
+					continue
+				}
+				expected, err := gobitstream.NewWriterWithOrder(size, byteOrder, bitOrder)
+				a.Nil(err)
+				a.Nil(writeFields(expected))
+				a.Nil(expected.Flush())
+				// stream holds the bytes of the bit stream starting at its first bit, whatever the byte order.
+				stream := append([]byte(nil), expected.Bytes()...)
+				if byteOrder == gobitstream.BigEndian {
+					for i, j := 0, len(stream)-1; i < j; i, j = i+1, j-1 {
+						stream[i], stream[j] = stream[j], stream[i]
+					}
+				}
+
+				buffer := make([]byte, 32)
+				r.Read(buffer)
+				original := append([]byte(nil), buffer...)
+
+				w, err := gobitstream.NewWriterOn(buffer, bitOffset, size, byteOrder, bitOrder)
+				a.Nil(err)
+				a.Nil(writeFields(w))
+
+				// positions maps the bits of buffer holding the stream to their index in the stream.
+				positions := make(map[int]int, size)
+				for k := 0; k < size; k++ {
+					if byteOrder == gobitstream.LittleEndian {
+						positions[bitOffset+k] = k
+					} else {
+						positions[(bitOffset/8+gobitstream.BitsToBytesSize(size)-1-k/8)*8+k%8] = k
+					}
+				}
+				msbFirst := bitOrder == gobitstream.MSBFirst
+				for pos := 0; pos < len(buffer)*8; pos++ {
+					if k, ok := positions[pos]; ok {
+						a.Equal(bitAt(stream, k, msbFirst), bitAt(buffer, pos, msbFirst), "bit %d inside the region", pos)
+					} else {
+						a.Equal(bitAt(original, pos, msbFirst), bitAt(buffer, pos, msbFirst), "bit %d outside the region", pos)
+					}
+				}
+
+				a.Equal(expected.Words(), w.Words())
+				v, err := w.ReadBitsAt(5, 11)
+				a.Nil(err)
+				a.Equal(uint64(0x5A5), v)
+				rd := w.Reader()
+				a.Equal(size, rd.Remaining())
+				v, err = rd.ReadNbitsUint64(5)
+				a.Nil(err)
+				a.Equal(uint64(0x15), v)
+
+				a.Nil(w.Flush())
+				a.Equal(expected.Bytes(), w.Bytes())
+				a.Equal(expected.Bytes(), w.AppendBytes(nil))
+			}
+		}
+	}
+}
+
+func TestNewWriterOnBigEndianFullRegion(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	expected := gobitstream.NewWriterBE(32)
+	buffer := []byte{0xEE, 0, 0, 0, 0, 0xEE}
+	w, err := gobitstream.NewWriterOn(buffer, 8, 32, gobitstream.BigEndian, gobitstream.LSBFirst)
+	a.Nil(err)
+	for _, field := range []struct{ nBits, val int }{{11, 0x123}, {11, 0x456}, {10, 0x3A5}} {
+		a.Nil(expected.WriteNbitsFromWord(field.nBits, uint64(field.val)))
+		a.Nil(w.WriteNbitsFromWord(field.nBits, uint64(field.val)))
+	}
+	a.Nil(expected.Flush())
+	a.Equal(expected.Bytes(), buffer[1:5])
+	a.Equal(byte(0xEE), buffer[0])
+	a.Equal(byte(0xEE), buffer[5])
+}
+
+func TestNewWriterOnBounds(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	buffer := []byte{0xFF, 0xFF, 0xFF, 0xFF}
+	w, err := gobitstream.NewWriterOn(buffer, 4, 20, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	a.Equal(uint64(0), w.Uint64())
+	a.Equal(1, len(w.CurrentWord()))
+	a.Equal(0, len(w.Words()))
+	a.Nil(w.WriteNbitsFromWord(16, 0))
+	err = w.WriteNbitsFromWord(5, 0)
+	a.Equal(gobitstream.OffsetOutOfRangeError, errors.Cause(err))
+	err = w.WriteNbitsFromBytes(8, []byte{0})
+	a.Equal(gobitstream.OffsetOutOfRangeError, errors.Cause(err))
+	a.Nil(w.WriteNbitsFromWord(4, 0))
+	a.Equal([]byte{0xF0, 0x00, 0x00, 0xFF}, buffer)
+
+	w.Reset()
+	a.Equal(0, len(w.Words()))
+	a.Nil(w.WriteNbitsFromWord(4, 0xA))
+	a.Equal([]byte{0xFA, 0x00, 0x00, 0xFF}, buffer)
+
+	testCases := []struct {
+		name       string
+		bitOffset  int
+		sizeInBits int
+	}{
+		{name: "negative offset", bitOffset: -1, sizeInBits: 8},
+		{name: "negative size", bitOffset: 0, sizeInBits: -8},
+		{name: "past the buffer", bitOffset: 9, sizeInBits: 24},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := gobitstream.NewWriterOn(buffer, tc.bitOffset, tc.sizeInBits, gobitstream.LittleEndian, gobitstream.LSBFirst)
+			a.Equal(gobitstream.OffsetOutOfRangeError, errors.Cause(err))
+		})
+	}
+	_, err = gobitstream.NewWriterOn(buffer, 0, 8, gobitstream.LittleEndian, gobitstream.BitOrder(2))
+	a.NotNil(err)
+	_, err = gobitstream.NewWriterOn(buffer, 4, 8, gobitstream.BigEndian, gobitstream.LSBFirst)
+	a.Equal(gobitstream.InvalidOffsetError, errors.Cause(err))
+}
@@ -0,0 +1,124 @@
+package gobitstream
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ io.Reader     = (*Reader)(nil)
+	_ io.ByteReader = (*Reader)(nil)
+	_ io.WriterTo   = (*Reader)(nil)
+	_ io.Writer     = (*Writer)(nil)
+	_ io.ByteWriter = (*Writer)(nil)
+	_ io.ReaderFrom = (*Writer)(nil)
+)
+
+// ioChunkSize is the size of the buffer used by WriteTo and ReadFrom.
+const ioChunkSize = 4096
+
+// Read reads up to len(p) bytes from the bit stream, each one taken as an 8 bits field at the current offset,
+// which does not need to be byte aligned. It implements io.Reader: io.EOF is returned once the bit stream is
+// exhausted, and io.ErrUnexpectedEOF if fewer than 8 bits are left.
+func (wr *Reader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	remaining := wr.Remaining()
+	if remaining == 0 {
+		return 0, io.EOF
+	}
+	n = remaining / 8
+	if n == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if n > len(p) {
+		n = len(p)
+	}
+	if _, err = wr.ReadNbitsBytesInto(p[:n], n*8); err != nil {
+		return 0, err
+	}
+	// Big-endian Readers return multi-byte values reversed, Read returns bytes in stream order.
+	if !wr.isLittleEndian {
+		reverseSlice(p[:n])
+	}
+	return n, nil
+}
+
+// ReadByte reads an 8 bits field at the current offset. It implements io.ByteReader.
+func (wr *Reader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := wr.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// WriteTo writes the remaining whole bytes of the bit stream to w, as Read would return them.
+// Fewer than 8 trailing bits are left unread. It implements io.WriterTo.
+func (wr *Reader) WriteTo(w io.Writer) (total int64, err error) {
+	var buf [ioChunkSize]byte
+	for wr.Remaining() >= 8 {
+		n, _ := wr.Read(buf[:])
+		written, err := w.Write(buf[:n])
+		total += int64(written)
+		if err != nil {
+			return total, err
+		}
+		if written != n {
+			return total, io.ErrShortWrite
+		}
+	}
+	return total, nil
+}
+
+// Write writes p to the bit stream, each byte as an 8 bits field at the current offset,
+// which does not need to be byte aligned. It implements io.Writer.
+func (wr *Writer) Write(p []byte) (n int, err error) {
+	for n+8 <= len(p) {
+		var word uint64
+		if wr.bitOrder == MSBFirst {
+			word = binary.BigEndian.Uint64(p[n:])
+		} else {
+			word = binary.LittleEndian.Uint64(p[n:])
+		}
+		if err = wr.WriteNbitsFromWord(64, word); err != nil {
+			return n, err
+		}
+		n += 8
+	}
+	for ; n < len(p); n++ {
+		if err = wr.WriteNbitsFromWord(8, uint64(p[n])); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// WriteByte writes c as an 8 bits field at the current offset. It implements io.ByteWriter.
+func (wr *Writer) WriteByte(c byte) error {
+	return wr.WriteNbitsFromWord(8, uint64(c))
+}
+
+// ReadFrom writes the bytes read from r until io.EOF, as Write does. It implements io.ReaderFrom.
+func (wr *Writer) ReadFrom(r io.Reader) (total int64, err error) {
+	var buf [ioChunkSize]byte
+	for {
+		n, errRead := r.Read(buf[:])
+		if n > 0 {
+			written, errWrite := wr.Write(buf[:n])
+			total += int64(written)
+			if errWrite != nil {
+				return total, errors.WithStack(errWrite)
+			}
+		}
+		if errRead == io.EOF {
+			return total, nil
+		}
+		if errRead != nil {
+			return total, errRead
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package gobitstream_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestReaderWriterIoUnaligned(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	payload := make([]byte, 5000)
+	r.Read(payload)
+
+	for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+		for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+			w, err := gobitstream.NewWriterWithOrder(3+len(payload)*8+5, byteOrder, bitOrder)
+			a.Nil(err)
+			a.Nil(w.WriteNbitsFromWord(3, 0x5))
+			n, err := w.Write(payload[:11])
+			a.Nil(err)
+			a.Equal(11, n)
+			a.Nil(w.WriteByte(payload[11]))
+			written, err := w.ReadFrom(bytes.NewReader(payload[12:]))
+			a.Nil(err)
+			a.Equal(int64(len(payload)-12), written)
+			a.Nil(w.WriteNbitsFromWord(5, 0x1F))
+
+			rd := w.Reader()
+			v, err := rd.ReadNbitsUint64(3)
+			a.Nil(err)
+			a.Equal(uint64(0x5), v)
+			b, err := rd.ReadByte()
+			a.Nil(err)
+			a.Equal(payload[0], b)
+			out := make([]byte, 9)
+			n, err = rd.Read(out)
+			a.Nil(err)
+			a.Equal(9, n)
+			a.Equal(payload[1:10], out)
+
+			var sink bytes.Buffer
+			copied, err := rd.WriteTo(&sink)
+			a.Nil(err)
+			a.Equal(int64(len(payload)-10), copied)
+			a.Equal(payload[10:], sink.Bytes())
+
+			// The 5 trailing bits are not a whole byte.
+			a.Equal(5, rd.Remaining())
+			_, err = rd.Read(out)
+			a.Equal(io.ErrUnexpectedEOF, err)
+			v, err = rd.ReadNbitsUint64(5)
+			a.Nil(err)
+			a.Equal(uint64(0x1F), v)
+			_, err = rd.ReadByte()
+			a.Equal(io.EOF, err)
+		}
+	}
+}
+
+func TestReaderWriterIoBytesMatchFields(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w, err := gobitstream.NewWriterWithOrder(32, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	a.Nil(w.WriteNbitsFromWord(4, 0xA))
+	_, err = w.Write([]byte{0x12, 0x34, 0x56})
+	a.Nil(err)
+	a.Nil(w.WriteNbitsFromWord(4, 0xB))
+	a.Nil(w.Flush())
+	a.Equal([]byte{0xA1, 0x23, 0x45, 0x6B}, w.Bytes())
+}
+
+func TestReaderWithEncodingBinary(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w, err := gobitstream.NewWriterWithOrder(1+32+16, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	a.Nil(w.WriteNbitsFromWord(1, 1))
+	a.Nil(binary.Write(w, binary.BigEndian, uint32(0xDEADBEEF)))
+	a.Nil(binary.Write(w, binary.BigEndian, uint16(0xCAFE)))
+
+	rd := w.Reader()
+	flag, err := rd.ReadNbitsUint64(1)
+	a.Nil(err)
+	a.Equal(uint64(1), flag)
+	var v32 uint32
+	var v16 uint16
+	a.Nil(binary.Read(rd, binary.BigEndian, &v32))
+	a.Nil(binary.Read(rd, binary.BigEndian, &v16))
+	a.Equal(uint32(0xDEADBEEF), v32)
+	a.Equal(uint16(0xCAFE), v16)
+}
+
+func TestWriterWriteOverflow(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewWriterLE(64)
+	n, err := w.Write([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	a.NotNil(err)
+	a.Equal(8, n)
+}
@@ -0,0 +1,88 @@
+package gobitstream
+
+import (
+	"github.com/pkg/errors"
+)
+
+// checkWrittenRange validates a field of up to 64 bits inside the bits written so far.
+func (wr *Writer) checkWrittenRange(bitOffset, nBits int) error {
+	if nBits <= 0 || nBits > 64 {
+		return errors.Wrapf(InvalidBitsSizeError, "nBits: %d", nBits)
+	}
+	if bitOffset < 0 || bitOffset+nBits > wr.offset {
+		return errors.Wrapf(OffsetOutOfRangeError, "bitOffset: %d, nBits: %d, written: %d", bitOffset, nBits, wr.offset)
+	}
+	return nil
+}
+
+// WriteBitsAt overwrites nBits number of bits, up to 64, at bitOffset with value, without moving the offset
+// where the next write appends. The field must lie within the bits written so far.
+// In strict mode a *FieldOverflowError is returned if value does not fit in nBits bits.
+func (wr *Writer) WriteBitsAt(bitOffset, nBits int, value uint64) error {
+	if err := wr.checkWrittenRange(bitOffset, nBits); err != nil {
+		return err
+	}
+	if wr.strict && !fitsUnsigned(value, uint64(nBits)) {
+		return &FieldOverflowError{Offset: uint64(bitOffset), Width: uint64(nBits), Value: value}
+	}
+	if wr.bitOrder == MSBFirst {
+		value = reverseField(value, nBits)
+	}
+	return wr.setField(bitOffset, nBits, value)
+}
+
+// ReadBitsAt returns nBits number of bits, up to 64, at bitOffset, without moving the offset where the next
+// write appends. The field must lie within the bits written so far.
+func (wr *Writer) ReadBitsAt(bitOffset, nBits int) (uint64, error) {
+	if err := wr.checkWrittenRange(bitOffset, nBits); err != nil {
+		return 0, err
+	}
+	value, err := wr.getField(bitOffset, nBits)
+	if err != nil {
+		return 0, err
+	}
+	if wr.bitOrder == MSBFirst {
+		value = reverseField(value, nBits)
+	}
+	return value, nil
+}
+
+// Reservation is a field reserved by Writer.Reserve, to be filled in once its value is known.
+type Reservation struct {
+	wr     *Writer
+	offset int
+	nBits  int
+}
+
+// Reserve writes nBits number of zero bits, up to 64, and returns a Reservation to set them later,
+// typically for length or checksum fields that depend on what is written after them.
+// With coverage tracking the reserved bits are reported as a gap until the Reservation is set.
+func (wr *Writer) Reserve(nBits int) (Reservation, error) {
+	if nBits <= 0 || nBits > 64 {
+		return Reservation{}, errors.Wrapf(InvalidBitsSizeError, "nBits: %d", nBits)
+	}
+	offset := wr.offset
+	if err := wr.WriteNbitsFromWord(nBits, 0); err != nil {
+		return Reservation{}, err
+	}
+	wr.markCoverage(offset, nBits, false)
+	return Reservation{wr: wr, offset: offset, nBits: nBits}, nil
+}
+
+// Offset returns the offset in bits of the reserved field.
+func (r Reservation) Offset() int {
+	return r.offset
+}
+
+// Width returns the width in bits of the reserved field.
+func (r Reservation) Width() int {
+	return r.nBits
+}
+
+// Set writes value to the reserved field. It can be called any number of times.
+func (r Reservation) Set(value uint64) error {
+	if r.wr == nil {
+		return errors.Wrap(UnexpectedCondition, "reservation was not returned by Reserve")
+	}
+	return r.wr.WriteBitsAt(r.offset, r.nBits, value)
+}
@@ -0,0 +1,148 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestWriterWriteBitsAt(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		bitOrder gobitstream.BitOrder
+		expected []byte
+	}{
+		{name: "LSB first", bitOrder: gobitstream.LSBFirst, expected: []byte{0x54, 0xA4, 0x6E, 0xFD}},
+		{name: "MSB first", bitOrder: gobitstream.MSBFirst, expected: []byte{0x4A, 0x45, 0xFD, 0x6E}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := gobitstream.NewWriterWithOrder(32, gobitstream.LittleEndian, tc.bitOrder)
+			a.Nil(err)
+			a.Nil(w.WriteNbitsFromWord(4, 0x4))
+			a.Nil(w.WriteNbitsFromWord(12, 0x000))
+			a.Nil(w.WriteNbitsFromWord(16, 0xFD6E))
+
+			a.Nil(w.WriteBitsAt(4, 12, 0xA45))
+			v, err := w.ReadBitsAt(4, 12)
+			a.Nil(err)
+			a.Equal(uint64(0xA45), v)
+			v, err = w.ReadBitsAt(16, 16)
+			a.Nil(err)
+			a.Equal(uint64(0xFD6E), v)
+
+			a.Nil(w.Flush())
+			a.Equal(tc.expected, w.Bytes())
+		})
+	}
+}
+
+func TestWriterWriteBitsAtDoesNotMoveOffset(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewGrowableWriterLE()
+	a.Nil(w.WriteNbitsFromWord(40, 0))
+	a.Nil(w.WriteBitsAt(30, 10, 0x3FF))
+	a.Nil(w.WriteNbitsFromWord(8, 0xAB))
+
+	rd := w.Reader()
+	a.Equal(48, rd.Remaining())
+	v, err := rd.ReadNbitsUint64(30)
+	a.Nil(err)
+	a.Equal(uint64(0), v)
+	v, err = rd.ReadNbitsUint64(10)
+	a.Nil(err)
+	a.Equal(uint64(0x3FF), v)
+	v, err = rd.ReadNbitsUint64(8)
+	a.Nil(err)
+	a.Equal(uint64(0xAB), v)
+}
+
+func TestWriterWriteBitsAtErrors(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewWriterLE(128)
+	a.Nil(w.WriteNbitsFromWord(16, 0xFFFF))
+
+	testCases := []struct {
+		name      string
+		bitOffset int
+		nBits     int
+		expected  error
+	}{
+		{name: "past written bits", bitOffset: 10, nBits: 7, expected: gobitstream.OffsetOutOfRangeError},
+		{name: "negative offset", bitOffset: -1, nBits: 4, expected: gobitstream.OffsetOutOfRangeError},
+		{name: "zero bits", bitOffset: 0, nBits: 0, expected: gobitstream.InvalidBitsSizeError},
+		{name: "too wide", bitOffset: 0, nBits: 65, expected: gobitstream.InvalidBitsSizeError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := w.WriteBitsAt(tc.bitOffset, tc.nBits, 0)
+			a.Equal(tc.expected, errors.Cause(err))
+			_, err = w.ReadBitsAt(tc.bitOffset, tc.nBits)
+			a.Equal(tc.expected, errors.Cause(err))
+		})
+	}
+
+	v, err := w.ReadBitsAt(0, 16)
+	a.Nil(err)
+	a.Equal(uint64(0xFFFF), v)
+}
+
+func TestWriterReserve(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+		w, err := gobitstream.NewWriterWithOrder(256, gobitstream.BigEndian, bitOrder)
+		a.Nil(err)
+
+		a.Nil(w.WriteNbitsFromWord(3, 0x5))
+		length, err := w.Reserve(13)
+		a.Nil(err)
+		a.Equal(3, length.Offset())
+		a.Equal(13, length.Width())
+		checksum, err := w.Reserve(8)
+		a.Nil(err)
+
+		payload := []uint64{0x12, 0x34, 0x56, 0x78, 0x9A}
+		var sum uint64
+		for _, b := range payload {
+			a.Nil(w.WriteNbitsFromWord(8, b))
+			sum += b
+		}
+		a.Nil(length.Set(uint64(len(payload) * 8)))
+		a.Nil(checksum.Set(sum))
+
+		rd := w.Reader()
+		v, err := rd.ReadNbitsUint64(3)
+		a.Nil(err)
+		a.Equal(uint64(0x5), v)
+		v, err = rd.ReadNbitsUint64(13)
+		a.Nil(err)
+		a.Equal(uint64(40), v)
+		v, err = rd.ReadNbitsUint64(8)
+		a.Nil(err)
+		a.Equal(sum&0xFF, v)
+		a.Equal(40, rd.Remaining())
+	}
+
+	w := gobitstream.NewWriterLE(64)
+	_, err := w.Reserve(65)
+	a.NotNil(err)
+	_, err = w.Reserve(0)
+	a.NotNil(err)
+	a.NotNil(gobitstream.Reservation{}.Set(1))
+	r, err := w.Reserve(64)
+	a.Nil(err)
+	_, err = w.Reserve(1)
+	a.NotNil(err)
+	a.Nil(r.Set(0xFFFFFFFFFFFFFFFF))
+	a.Equal(uint64(0xFFFFFFFFFFFFFFFF), w.Uint64())
+}
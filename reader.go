@@ -1,25 +1,30 @@
 // Package gobitstream provides a bit stream reader and a bit stream writer in Go, allowing reading bits from a byte slice.
-// It supports both little-endian and big-endian byte orders.
+// It supports both little-endian and big-endian byte orders, and both LSB-first and MSB-first bit orders.
 package gobitstream
 
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
+	"math/bits"
 
 	"github.com/pkg/errors"
 )
 
 // Reader is a bit stream reader that allows reading bits from a byte slice.
+// It implements io.Reader, io.ByteReader and io.WriterTo, reading bytes at the current bit offset.
 type Reader struct {
 	currBitIndex   int      // Current bit index in the current byte
 	currWordIndex  int      // Current word index in the inWord slice
 	offset         int      // Current offset in the bit stream
 	size           int      // Size of the bit stream in bits
+	base           int      // Bit offset in inWord where the bit stream starts, non zero for sub-readers
 	inWord         []uint64 // Slice of uint64 words that represents the input byte slice
 	resWordsBuffer []uint64 // Buffer to store the resulting words read from the bit stream
 	in             []byte   // Input byte slice from which bits are read
 	resBytesBuffer []byte   // Buffer to store the resulting bytes read from the bit stream
 	isLittleEndian bool     // Boolean flag indicating whether the byte order is little-endian
+	bitOrder       BitOrder // Order of the bits within each byte and field
 }
 
 // NewReader creates a new Reader instance with the specified size in bits and input byte slice.
@@ -74,6 +79,36 @@ func NewReaderBE(sizeInBits int, in []byte) (wr *Reader, err error) {
 	return wr, errors.WithStack(err)
 }
 
+// NewReaderFromWords creates a new little-endian Reader that reads sizeInBits bits directly from words, without copying them.
+// words uses the layout returned by Writer.Words: the first bit of the stream is the least significant bit of words[0].
+// The caller must not modify words while the Reader is in use, or the modifications will be visible to the reads.
+// It returns an error if words holds fewer than sizeInBits bits.
+func NewReaderFromWords(sizeInBits int, words []uint64) (*Reader, error) {
+	if sizeInBits < 0 || len(words)*64 < sizeInBits {
+		err := errors.Wrapf(InvalidInputSliceSizeError, "words hold %d bits, sizeInBits: %d", len(words)*64, sizeInBits)
+		return nil, errors.WithStack(err)
+	}
+	return &Reader{
+		size:           sizeInBits,
+		inWord:         words,
+		isLittleEndian: true,
+	}, nil
+}
+
+// NewReaderFromWordsWithOrder works like NewReaderFromWords with the specified bit order. words uses the layout
+// returned by Words of a Writer created with the same bit order.
+func NewReaderFromWordsWithOrder(sizeInBits int, words []uint64, bitOrder BitOrder) (*Reader, error) {
+	if err := checkOrders(LittleEndian, bitOrder); err != nil {
+		return nil, err
+	}
+	wr, err := NewReaderFromWords(sizeInBits, words)
+	if err != nil {
+		return nil, err
+	}
+	wr.bitOrder = bitOrder
+	return wr, nil
+}
+
 // Reset resets the Reader to its initial state, including resetting the current bit and word indices, offset, and byte order.
 func (wr *Reader) Reset() {
 	wr.currWordIndex = 0
@@ -86,6 +121,9 @@ func (wr *Reader) Reset() {
 	}
 }
 
+// inWordOffset returns the current offset as a bit offset in inWord.
+func (wr *Reader) inWordOffset() uint64 { return uint64(wr.base + wr.offset) }
+
 // checkNbitsSize checks the size of nBits and validates it against the Reader's offset and size.
 // It returns an error if the size is invalid.
 func (wr *Reader) checkNbitsSize(nBits int) error {
@@ -95,7 +133,7 @@ func (wr *Reader) checkNbitsSize(nBits int) error {
 		return errors.WithStack(err)
 	} else if nBits+wr.offset > wr.size {
 		err := errors.New("invalid bits sizeInBytes")
-		errWrap := fmt.Sprintf("nBits+wr.accOffset > wr.size, nBits: %d, accOffset: %d, wr.size: %d", nBits, wr.offset, wr.size)
+		errWrap := fmt.Sprintf("reading past the end of the bit stream, nBits: %d, offset: %d, size: %d", nBits, wr.offset, wr.size)
 		err = errors.Wrap(err, errWrap)
 		return errors.WithStack(err)
 	}
@@ -124,11 +162,22 @@ func (wr *Reader) calcParams(nBits, offset int) (sizeInBytes, wordOffset, localO
 
 // ReadNbitsWords64 reads nBits number of bits from the bit stream and returns the resulting words as a slice of uint64 values.
 // It also updates the offset in the bit stream. An error is returned if the number of bits to be read is invalid.
+// With MSBFirst bit order the first bit read is the most significant bit of the result.
 func (wr *Reader) ReadNbitsWords64(nBits int) (res []uint64, err error) {
+	resWords, err := wr.readRawWords(nBits)
+	if err == nil && wr.bitOrder == MSBFirst {
+		reverseFieldWords(resWords, nBits)
+	}
+	return resWords, err
+}
+
+// readRawWords reads nBits number of bits from the bit stream in stream order: the first bit read is the least
+// significant bit of the result, whatever the bit order. It also updates the offset in the bit stream.
+func (wr *Reader) readRawWords(nBits int) (res []uint64, err error) {
 	if err = wr.checkNbitsSize(nBits); err != nil {
 		return res, err
 	}
-	resWords, err := GetFieldFromSlice(uint64(nBits), uint64(wr.offset), wr.inWord, nil)
+	resWords, err := GetFieldFromSlice(uint64(nBits), wr.inWordOffset(), wr.inWord, nil)
 	wr.offset += nBits
 	return resWords, errors.WithStack(err)
 }
@@ -136,11 +185,24 @@ func (wr *Reader) ReadNbitsWords64(nBits int) (res []uint64, err error) {
 // ReadNbitsUint64 reads nBits number of bits from the bit stream and returns the resulting uint64 value.
 // It also updates the offset in the bit stream. An error is returned if the number of bits to be read is invalid.
 func (wr *Reader) ReadNbitsUint64(nBits int) (res uint64, err error) {
+	if res, err = wr.PeekNbitsUint64(nBits); err != nil {
+		return 0, err
+	}
+	wr.offset += nBits
+	return res, nil
+}
+
+// PeekNbitsUint64 returns the next nBits number of bits of the bit stream as a uint64 value without consuming them.
+// An error is returned if the number of bits to be read is invalid, an InvalidBitsSizeError if it exceeds 64.
+func (wr *Reader) PeekNbitsUint64(nBits int) (res uint64, err error) {
+	if nBits > 64 {
+		return 0, errors.Wrapf(InvalidBitsSizeError, "nBits: %d exceeds 64", nBits)
+	}
 	if err = wr.checkNbitsSize(nBits); err != nil {
 		return res, errors.WithStack(err)
 	}
 
-	resWords, err := GetFieldFromSlice(uint64(nBits), uint64(wr.offset), wr.inWord, nil)
+	resWords, err := GetFieldFromSlice(uint64(nBits), wr.inWordOffset(), wr.inWord, nil)
 
 	if err != nil {
 		err = errors.Wrapf(err, "width: %d, offset %d", nBits, wr.offset)
@@ -151,17 +213,139 @@ func (wr *Reader) ReadNbitsUint64(nBits int) (res uint64, err error) {
 		err = errors.Wrapf(err, "nBits: %d", nBits)
 		return 0, errors.WithStack(err)
 	}
-	wr.offset += nBits
+	if wr.bitOrder == MSBFirst {
+		return reverseField(resWords[0], nBits), nil
+	}
 	return resWords[0], nil
 }
 
+// runLength counts the consecutive bits equal to bit starting at the current offset, without consuming them.
+// It scans a word at a time and reports whether a different bit was found before the end of the bit stream.
+func (wr *Reader) runLength(bit uint64) (n int, terminated bool) {
+	for pos := wr.offset; pos < wr.size; {
+		width := wr.size - pos
+		if width > 64 {
+			width = 64
+		}
+		chunk, err := Get64BitsFieldFromSlice(wr.inWord, uint64(width), uint64(wr.base+pos))
+		if err != nil {
+			return n, false
+		}
+		if bit != 0 {
+			chunk = ^chunk
+			if width < 64 {
+				chunk &= 1<<width - 1
+			}
+		}
+		if chunk != 0 {
+			return n + bits.TrailingZeros64(chunk), true
+		}
+		n += width
+		pos += width
+	}
+	return n, false
+}
+
+// SkipBits advances the offset in the bit stream by nBits number of bits without reading them.
+// An error is returned if nBits is negative or goes past the end of the bit stream.
+func (wr *Reader) SkipBits(nBits int) error {
+	if nBits < 0 || nBits > wr.Remaining() {
+		err := errors.Wrapf(OffsetOutOfRangeError, "skip: %d, offset: %d, size: %d", nBits, wr.offset, wr.size)
+		return errors.WithStack(err)
+	}
+	wr.offset += nBits
+	return nil
+}
+
+// SeekBit sets the offset for the next read to offset bits, interpreted according to whence:
+// io.SeekStart means relative to the start of the bit stream, io.SeekCurrent means relative to the current offset,
+// and io.SeekEnd means relative to the end. It follows the io.Seeker contract, counting in bits instead of bytes,
+// and returns the new offset relative to the start of the bit stream.
+// Seeking before the start or past the end of the bit stream is an error.
+func (wr *Reader) SeekBit(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = int64(wr.offset) + offset
+	case io.SeekEnd:
+		abs = int64(wr.size) + offset
+	default:
+		return int64(wr.offset), errors.Wrapf(InvalidOffsetError, "invalid whence: %d", whence)
+	}
+	if abs < 0 || abs > int64(wr.size) {
+		err := errors.Wrapf(OffsetOutOfRangeError, "seek offset: %d, size: %d", abs, wr.size)
+		return int64(wr.offset), errors.WithStack(err)
+	}
+	wr.offset = int(abs)
+	return abs, nil
+}
+
+// SubReader returns a new Reader limited to the next nBits bits of the bit stream, and advances the offset past them.
+// The sub-reader shares the underlying words and has its own offset, starting at 0. Reads past the end of its
+// window fail instead of consuming the fields that follow in the parent bit stream.
+// An error is returned if nBits is negative or goes past the end of the bit stream.
+func (wr *Reader) SubReader(nBits int) (*Reader, error) {
+	if nBits < 0 || nBits > wr.Remaining() {
+		err := errors.Wrapf(OffsetOutOfRangeError, "sub-reader size: %d, offset: %d, size: %d", nBits, wr.offset, wr.size)
+		return nil, errors.WithStack(err)
+	}
+	sub := &Reader{
+		base:           wr.base + wr.offset,
+		size:           nBits,
+		inWord:         wr.inWord,
+		isLittleEndian: wr.isLittleEndian,
+		bitOrder:       wr.bitOrder,
+	}
+	wr.offset += nBits
+	return sub, nil
+}
+
+// Mark is an opaque checkpoint of a Reader position, created by Reader.Mark and restored by Reader.Rewind.
+type Mark struct {
+	offset int
+}
+
+// Mark returns a checkpoint of the current offset, to come back to it with Rewind.
+func (wr *Reader) Mark() Mark { return Mark{offset: wr.offset} }
+
+// Rewind moves the offset back, or forward, to the checkpoint m returned by Mark.
+// An error is returned if m is out of the range of the bit stream, for instance when it comes from another Reader.
+func (wr *Reader) Rewind(m Mark) error {
+	if m.offset < 0 || m.offset > wr.size {
+		err := errors.Wrapf(OffsetOutOfRangeError, "mark offset: %d, size: %d", m.offset, wr.size)
+		return errors.WithStack(err)
+	}
+	wr.offset = m.offset
+	return nil
+}
+
+// Clone returns a new Reader sharing the underlying words of wr, with an independent offset starting at the
+// current one. Cloning does not copy the bit stream, so it is cheap enough for speculative parsing.
+func (wr *Reader) Clone() *Reader {
+	clone := *wr
+	clone.resWordsBuffer = nil
+	clone.resBytesBuffer = nil
+	return &clone
+}
+
+// Offset returns the current offset in the bit stream, in bits.
+func (wr *Reader) Offset() int { return wr.offset }
+
+// Remaining returns the number of bits left to read in the bit stream.
+func (wr *Reader) Remaining() int { return wr.size - wr.offset }
+
 // ReadNbitsBytes reads nBits number of bits from the bit stream and returns the resulting bytes value.
 // It also updates the offset in the bit stream. An error is returned if the number of bits to be read is invalid.
+// With MSBFirst bit order the bits are copied in stream order starting at the most significant bit of the first byte.
+// On little-endian Readers the returned slice may alias an internal buffer that is overwritten by the next call;
+// use ReadNbitsBytesInto to control where the bytes are stored.
 func (wr *Reader) ReadNbitsBytes(nBits int) (outBytes []byte, err error) {
 	if err = wr.checkNbitsSize(nBits); err != nil {
 		return outBytes, err
 	}
-	resultWords, err := wr.ReadNbitsWords64(nBits)
+	resultWords, err := wr.readRawWords(nBits)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -184,6 +368,10 @@ func (wr *Reader) ReadNbitsBytes(nBits int) (outBytes []byte, err error) {
 
 	resultBytes = resultBytes[:sizeInBytes]
 
+	if wr.bitOrder == MSBFirst {
+		reverseBitsInBytes(resultBytes)
+	}
+
 	if !wr.isLittleEndian {
 		outBytes = make([]byte, len(resultBytes))
 		_ = copy(outBytes, resultBytes)
@@ -194,6 +382,68 @@ func (wr *Reader) ReadNbitsBytes(nBits int) (outBytes []byte, err error) {
 	return resultBytes, nil
 }
 
+// ReadNbitsWordsInto reads nBits number of bits from the bit stream into dst, least significant word first,
+// and returns the number of words written. dst must hold at least (nBits+63)/64 words. dst stays owned by the
+// caller: the Reader does not keep any reference to it, and no memory is allocated.
+// It also updates the offset in the bit stream. An error is returned if the number of bits to be read is invalid.
+func (wr *Reader) ReadNbitsWordsInto(dst []uint64, nBits int) (int, error) {
+	if err := wr.checkNbitsSize(nBits); err != nil {
+		return 0, err
+	}
+	nWords := sizeInWords(nBits)
+	if len(dst) < nWords {
+		err := errors.Wrapf(InvalidInputSliceSizeError, "wanted words: %d, dst size: %d", nWords, len(dst))
+		return 0, errors.WithStack(err)
+	}
+	res, err := GetFieldFromSlice(uint64(nBits), wr.inWordOffset(), wr.inWord, dst)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if wr.bitOrder == MSBFirst {
+		reverseFieldWords(res, nBits)
+	}
+	wr.offset += nBits
+	return nWords, nil
+}
+
+// ReadNbitsBytesInto reads nBits number of bits from the bit stream into dst, with the same layout as ReadNbitsBytes,
+// and returns the number of bytes written. dst must hold at least (nBits+7)/8 bytes. dst stays owned by the
+// caller: the Reader does not keep any reference to it, and no memory is allocated once the internal word scratch
+// buffer has grown to the largest nBits requested.
+// It also updates the offset in the bit stream. An error is returned if the number of bits to be read is invalid.
+func (wr *Reader) ReadNbitsBytesInto(dst []byte, nBits int) (int, error) {
+	if err := wr.checkNbitsSize(nBits); err != nil {
+		return 0, err
+	}
+	nBytes := BitsToBytesSize(nBits)
+	if len(dst) < nBytes {
+		err := errors.Wrapf(InvalidInputSliceSizeError, "wanted bytes: %d, dst size: %d", nBytes, len(dst))
+		return 0, errors.WithStack(err)
+	}
+
+	nWords := sizeInWords(nBits)
+	if cap(wr.resWordsBuffer) < nWords {
+		wr.resWordsBuffer = make([]uint64, nWords)
+	}
+	words, err := GetFieldFromSlice(uint64(nBits), wr.inWordOffset(), wr.inWord, wr.resWordsBuffer[:nWords])
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	out := dst[:nBytes]
+	for i := range out {
+		out[i] = byte(words[i/8] >> (8 * (i % 8)))
+	}
+	if wr.bitOrder == MSBFirst {
+		reverseBitsInBytes(out)
+	}
+	if !wr.isLittleEndian {
+		reverseSlice(out)
+	}
+	wr.offset += nBits
+	return nBytes, nil
+}
+
 func (wr *Reader) Words() []uint64 { return wr.inWord }
 
 // ShiftSliceOfUint64Left performs a left shift on a slice of uint64 values by a given shift count.
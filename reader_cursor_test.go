@@ -0,0 +1,98 @@
+package gobitstream_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestReaderPeek(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderLE(24, []byte{0xAB, 0xCD, 0xEF})
+	a.Nil(err)
+
+	v, err := rd.PeekNbitsUint64(4)
+	a.Nil(err)
+	a.Equal(uint64(0xB), v)
+	a.Equal(0, rd.Offset())
+
+	v, err = rd.ReadNbitsUint64(4)
+	a.Nil(err)
+	a.Equal(uint64(0xB), v)
+	a.Equal(4, rd.Offset())
+	a.Equal(20, rd.Remaining())
+
+	v, err = rd.PeekNbitsUint64(20)
+	a.Nil(err)
+	a.Equal(uint64(0xEFCDA), v)
+
+	_, err = rd.PeekNbitsUint64(21)
+	a.NotNil(err)
+	a.Equal(4, rd.Offset())
+}
+
+func TestReaderSkip(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderLE(24, []byte{0xAB, 0xCD, 0xEF})
+	a.Nil(err)
+
+	a.Nil(rd.SkipBits(0))
+	a.Nil(rd.SkipBits(8))
+	v, err := rd.ReadNbitsUint64(8)
+	a.Nil(err)
+	a.Equal(uint64(0xCD), v)
+
+	a.NotNil(rd.SkipBits(9))
+	a.NotNil(rd.SkipBits(-1))
+	a.Equal(16, rd.Offset())
+
+	a.Nil(rd.SkipBits(8))
+	a.Equal(0, rd.Remaining())
+}
+
+func TestReaderSeekBit(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderLE(24, []byte{0xAB, 0xCD, 0xEF})
+	a.Nil(err)
+
+	testCases := []struct {
+		name     string
+		offset   int64
+		whence   int
+		expected int64
+		fails    bool
+	}{
+		{name: "start", offset: 8, whence: io.SeekStart, expected: 8},
+		{name: "current forward", offset: 4, whence: io.SeekCurrent, expected: 12},
+		{name: "current backward", offset: -12, whence: io.SeekCurrent, expected: 0},
+		{name: "end", offset: -8, whence: io.SeekEnd, expected: 16},
+		{name: "exact end", offset: 0, whence: io.SeekEnd, expected: 24},
+		{name: "before start", offset: -1, whence: io.SeekStart, expected: 24, fails: true},
+		{name: "past end", offset: 1, whence: io.SeekEnd, expected: 24, fails: true},
+		{name: "invalid whence", offset: 0, whence: 7, expected: 24, fails: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pos, err := rd.SeekBit(tc.offset, tc.whence)
+			if tc.fails {
+				a.NotNil(err)
+			} else {
+				a.Nil(err)
+			}
+			a.Equal(tc.expected, pos)
+			a.Equal(int(tc.expected), rd.Offset())
+		})
+	}
+
+	_, err = rd.SeekBit(16, io.SeekStart)
+	a.Nil(err)
+	v, err := rd.ReadNbitsUint64(8)
+	a.Nil(err)
+	a.Equal(uint64(0xEF), v)
+}
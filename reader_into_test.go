@@ -0,0 +1,81 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestReadNbitsIntoMatchesAllocatingReads(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	in := make([]byte, 300)
+	r.Read(in)
+
+	for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+		for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+			rdAlloc, err := gobitstream.NewReaderWithOrder(len(in)*8, in, byteOrder, bitOrder)
+			a.Nil(err)
+			rdInto, err := gobitstream.NewReaderWithOrder(len(in)*8, in, byteOrder, bitOrder)
+			a.Nil(err)
+
+			dstBytes := make([]byte, 64)
+			dstWords := make([]uint64, 8)
+			for rdAlloc.Remaining() > 0 {
+				width := r.Intn(400) + 1
+				if width > rdAlloc.Remaining() {
+					width = rdAlloc.Remaining()
+				}
+				if r.Intn(2) == 0 {
+					expected, err := rdAlloc.ReadNbitsBytes(width)
+					a.Nil(err)
+					n, err := rdInto.ReadNbitsBytesInto(dstBytes, width)
+					a.Nil(err)
+					a.Equal(expected, dstBytes[:n])
+				} else {
+					expected, err := rdAlloc.ReadNbitsWords64(width)
+					a.Nil(err)
+					n, err := rdInto.ReadNbitsWordsInto(dstWords, width)
+					a.Nil(err)
+					a.Equal(expected, dstWords[:n])
+				}
+				a.Equal(rdAlloc.Offset(), rdInto.Offset())
+			}
+		}
+	}
+}
+
+func TestReadNbitsIntoErrors(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderLE(80, make([]byte, 10))
+	a.Nil(err)
+
+	_, err = rd.ReadNbitsBytesInto(make([]byte, 1), 9)
+	a.NotNil(err)
+	_, err = rd.ReadNbitsWordsInto(make([]uint64, 1), 65)
+	a.NotNil(err)
+	_, err = rd.ReadNbitsWordsInto(make([]uint64, 2), 81)
+	a.NotNil(err)
+	a.Equal(0, rd.Offset())
+}
+
+func TestReadNbitsIntoDoesNotAllocate(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	in := make([]byte, 1024)
+	rd, err := gobitstream.NewReaderBE(len(in)*8, in)
+	a.Nil(err)
+
+	dstBytes := make([]byte, 32)
+	dstWords := make([]uint64, 4)
+	allocs := testing.AllocsPerRun(100, func() {
+		if rd.Remaining() < 400 {
+			rd.Reset()
+		}
+		_, _ = rd.ReadNbitsBytesInto(dstBytes, 200)
+		_, _ = rd.ReadNbitsWordsInto(dstWords, 200)
+	})
+	a.Equal(float64(0), allocs)
+}
@@ -0,0 +1,70 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestReaderMarkRewind(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderLE(32, []byte{0x01, 0x02, 0x03, 0x04})
+	a.Nil(err)
+
+	_, err = rd.ReadNbitsUint64(8)
+	a.Nil(err)
+	mark := rd.Mark()
+
+	// First alternative: a 16 bits field that fails validation.
+	v, err := rd.ReadNbitsUint64(16)
+	a.Nil(err)
+	a.Equal(uint64(0x0302), v)
+
+	// Second alternative: two 8 bits fields.
+	a.Nil(rd.Rewind(mark))
+	a.Equal(8, rd.Offset())
+	v, err = rd.ReadNbitsUint64(8)
+	a.Nil(err)
+	a.Equal(uint64(0x02), v)
+
+	end := rd.Mark()
+	a.Nil(rd.Rewind(mark))
+	a.Nil(rd.Rewind(end))
+	a.Equal(16, rd.Offset())
+
+	other, err := gobitstream.NewReaderLE(64, make([]byte, 8))
+	a.Nil(err)
+	a.Nil(other.SkipBits(64))
+	a.NotNil(rd.Rewind(other.Mark()))
+	a.Equal(16, rd.Offset())
+}
+
+func TestReaderClone(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderLE(32, []byte{0x01, 0x02, 0x03, 0x04})
+	a.Nil(err)
+	_, err = rd.ReadNbitsUint64(8)
+	a.Nil(err)
+
+	clone := rd.Clone()
+	a.Equal(8, clone.Offset())
+
+	v, err := clone.ReadNbitsUint64(16)
+	a.Nil(err)
+	a.Equal(uint64(0x0302), v)
+	a.Equal(8, rd.Offset())
+
+	b1, err := rd.ReadNbitsBytes(8)
+	a.Nil(err)
+	b2, err := clone.ReadNbitsBytes(8)
+	a.Nil(err)
+	a.Equal([]byte{0x02}, b1)
+	a.Equal([]byte{0x04}, b2)
+
+	clone.Reset()
+	a.Equal(0, clone.Offset())
+	a.Equal(16, rd.Offset())
+}
@@ -0,0 +1,85 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestSubReader(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewWriterLE(256)
+	a.Nil(w.WriteNbitsFromWord(8, 0x2A))
+	a.Nil(w.WriteNbitsFromWord(12, 0xABC))
+	a.Nil(w.WriteNbitsFromWord(64, 0x0123456789ABCDEF))
+	a.Nil(w.WriteNbitsFromWord(4, 0x5))
+	a.Nil(w.WriteNbitsFromWord(16, 0xBEEF))
+	a.Nil(w.Flush())
+
+	rd, err := gobitstream.NewReaderLE(104, w.Bytes())
+	a.Nil(err)
+	_, err = rd.ReadNbitsUint64(8)
+	a.Nil(err)
+
+	sub, err := rd.SubReader(80)
+	a.Nil(err)
+	a.Equal(88, rd.Offset())
+	a.Equal(0, sub.Offset())
+	a.Equal(80, sub.Remaining())
+
+	v, err := rd.ReadNbitsUint64(16)
+	a.Nil(err)
+	a.Equal(uint64(0xBEEF), v)
+
+	v, err = sub.ReadNbitsUint64(12)
+	a.Nil(err)
+	a.Equal(uint64(0xABC), v)
+
+	nested, err := sub.SubReader(64)
+	a.Nil(err)
+	words, err := nested.ReadNbitsWords64(64)
+	a.Nil(err)
+	a.Equal([]uint64{0x0123456789ABCDEF}, words)
+	_, err = nested.ReadNbitsUint64(1)
+	a.NotNil(err)
+
+	_, err = sub.ReadNbitsUint64(5)
+	a.NotNil(err)
+	v, err = sub.ReadNbitsUint64(4)
+	a.Nil(err)
+	a.Equal(uint64(0x5), v)
+	a.Equal(0, sub.Remaining())
+
+	sub.Reset()
+	v, err = sub.PeekNbitsUint64(12)
+	a.Nil(err)
+	a.Equal(uint64(0xABC), v)
+}
+
+func TestSubReaderBounds(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	rd, err := gobitstream.NewReaderWithOrder(16, []byte{0x80, 0x01}, gobitstream.LittleEndian, gobitstream.MSBFirst)
+	a.Nil(err)
+	_, err = rd.SubReader(17)
+	a.NotNil(err)
+	_, err = rd.SubReader(-1)
+	a.NotNil(err)
+
+	sub, err := rd.SubReader(9)
+	a.Nil(err)
+	n, err := sub.ReadUnary(1)
+	a.Nil(err)
+	a.Equal(uint64(0), n)
+	// The one bit that follows the window in the parent must not terminate the code.
+	_, err = sub.ReadUnary(1)
+	a.NotNil(err)
+
+	rest, err := rd.SubReader(7)
+	a.Nil(err)
+	v, err := rest.ReadNbitsUint64(7)
+	a.Nil(err)
+	a.Equal(uint64(1), v)
+}
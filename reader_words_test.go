@@ -0,0 +1,84 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestNewReaderFromWords(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	words := make([]uint64, 10)
+	for i := range words {
+		words[i] = r.Uint64()
+	}
+	in := make([]byte, 0, len(words)*8)
+	for _, word := range words {
+		for i := 0; i < 8; i++ {
+			in = append(in, byte(word>>(8*i)))
+		}
+	}
+
+	const sizeInBits = 10*64 - 5
+	rdBytes, err := gobitstream.NewReaderLE(sizeInBits, in)
+	a.Nil(err)
+	rdWords, err := gobitstream.NewReaderFromWords(sizeInBits, words)
+	a.Nil(err)
+
+	for rdBytes.Remaining() > 0 {
+		width := r.Intn(64) + 1
+		if width > rdBytes.Remaining() {
+			width = rdBytes.Remaining()
+		}
+		expected, err := rdBytes.ReadNbitsUint64(width)
+		a.Nil(err)
+		actual, err := rdWords.ReadNbitsUint64(width)
+		a.Nil(err)
+		a.Equal(expected, actual)
+	}
+	_, err = rdWords.ReadNbitsUint64(1)
+	a.NotNil(err)
+}
+
+func TestNewReaderFromWordsDoesNotCopy(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	words := []uint64{0x1234}
+	rd, err := gobitstream.NewReaderFromWords(16, words)
+	a.Nil(err)
+
+	words[0] = 0xABCD
+	v, err := rd.ReadNbitsUint64(16)
+	a.Nil(err)
+	a.Equal(uint64(0xABCD), v)
+
+	_, err = gobitstream.NewReaderFromWords(65, words)
+	a.NotNil(err)
+}
+
+func TestWriterReader(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewWriterLE(128)
+	a.Nil(w.WriteNbitsFromWord(12, 0xABC))
+	a.Nil(w.WriteNbitsFromWord(64, 0x0123456789ABCDEF))
+
+	rd := w.Reader()
+	a.Equal(76, rd.Remaining())
+	v, err := rd.ReadNbitsUint64(12)
+	a.Nil(err)
+	a.Equal(uint64(0xABC), v)
+	v, err = rd.ReadNbitsUint64(64)
+	a.Nil(err)
+	a.Equal(uint64(0x0123456789ABCDEF), v)
+	_, err = rd.ReadNbitsUint64(1)
+	a.NotNil(err)
+
+	wBE := gobitstream.NewWriterBE(24)
+	a.Nil(wBE.WriteNbitsFromBytes(24, []byte{0x01, 0x02, 0x03}))
+	out, err := wBE.Reader().ReadNbitsBytes(24)
+	a.Nil(err)
+	a.Equal([]byte{0x01, 0x02, 0x03}, out)
+}
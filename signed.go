@@ -0,0 +1,65 @@
+package gobitstream
+
+import (
+	"github.com/pkg/errors"
+)
+
+// signExtend interprets the low widthInBits bits of val as a two's complement value.
+func signExtend(val, widthInBits uint64) int64 {
+	shift := 64 - widthInBits
+	return int64(val<<shift) >> shift
+}
+
+// fitsSigned reports whether val can be represented as a two's complement value of widthInBits bits.
+func fitsSigned(val int64, widthInBits uint64) bool {
+	if widthInBits >= 64 {
+		return true
+	}
+	limit := int64(1) << (widthInBits - 1)
+	return val >= -limit && val < limit
+}
+
+// GetSigned64BitsFieldFromSlice extracts a signed two's complement field of bits from a slice of uint64.
+// It works like Get64BitsFieldFromSlice and sign-extends the extracted field to an int64.
+func GetSigned64BitsFieldFromSlice(inputFieldSlice []uint64, widthInBits, offsetInBits uint64) (int64, error) {
+	field, err := Get64BitsFieldFromSlice(inputFieldSlice, widthInBits, offsetInBits)
+	if err != nil {
+		return 0, err
+	}
+	return signExtend(field, widthInBits), nil
+}
+
+// SetSigned64BitsFieldToSlice sets a signed two's complement field of bits in a slice of uint64.
+// It works like Set64BitsFieldToSlice, but instead of masking the input it returns a *FieldOverflowError
+// if inputField cannot be represented in widthInBits bits.
+func SetSigned64BitsFieldToSlice(destinationField []uint64, inputField int64, widthInBits, offsetInBits uint64) ([]uint64, error) {
+	if widthInBits > 0 && widthInBits <= 64 && !fitsSigned(inputField, widthInBits) {
+		return nil, &FieldOverflowError{Offset: offsetInBits, Width: widthInBits, Value: uint64(inputField), Signed: true}
+	}
+	return Set64BitsFieldToSlice(destinationField, uint64(inputField), widthInBits, offsetInBits)
+}
+
+// ReadNbitsInt64 reads nBits number of bits from the bit stream and returns them sign-extended as an int64 value.
+// It also updates the offset in the bit stream. An error is returned if the number of bits to be read is invalid.
+func (wr *Reader) ReadNbitsInt64(nBits int) (int64, error) {
+	if nBits > 64 {
+		return 0, errors.Wrapf(InvalidBitsSizeError, "nBits: %d exceeds 64", nBits)
+	}
+	val, err := wr.ReadNbitsUint64(nBits)
+	if err != nil {
+		return 0, err
+	}
+	return signExtend(val, uint64(nBits)), nil
+}
+
+// WriteNbitsFromInt64 writes val as a two's complement field of nBits number of bits.
+// If val cannot be represented in nBits bits a *FieldOverflowError is returned and nothing is written.
+func (wr *Writer) WriteNbitsFromInt64(nBits int, val int64) error {
+	if nBits <= 0 || nBits > 64 {
+		return errors.Wrapf(InvalidBitsSizeError, "nBits: %d", nBits)
+	}
+	if !fitsSigned(val, uint64(nBits)) {
+		return &FieldOverflowError{Offset: uint64(wr.offset), Width: uint64(nBits), Value: uint64(val), Signed: true}
+	}
+	return wr.writeNbits(nBits, uint64(val))
+}
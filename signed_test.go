@@ -0,0 +1,109 @@
+package gobitstream_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestGetSigned64BitsFieldFromSlice(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		slice    []uint64
+		width    uint64
+		offset   uint64
+		expected int64
+	}{
+		{name: "positive", slice: []uint64{0x0FFF}, width: 13, offset: 0, expected: 0x0FFF},
+		{name: "minus one", slice: []uint64{0x1FFF0}, width: 13, offset: 4, expected: -1},
+		{name: "most negative", slice: []uint64{0x1000}, width: 13, offset: 0, expected: -4096},
+		{name: "cross word", slice: []uint64{0xF000000000000000, 0x1}, width: 5, offset: 60, expected: -1},
+		{name: "full width", slice: []uint64{0x8000000000000000}, width: 64, offset: 0, expected: math.MinInt64},
+		{name: "single bit", slice: []uint64{0x1}, width: 1, offset: 0, expected: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := gobitstream.GetSigned64BitsFieldFromSlice(tc.slice, tc.width, tc.offset)
+			a.Nil(err)
+			a.Equal(tc.expected, actual)
+		})
+	}
+
+	_, err := gobitstream.GetSigned64BitsFieldFromSlice([]uint64{0}, 0, 0)
+	a.NotNil(err)
+}
+
+func TestSetSigned64BitsFieldToSlice(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	dst := []uint64{0xFFFFFFFFFFFFFFFF, 0}
+	dst, err := gobitstream.SetSigned64BitsFieldToSlice(dst, -2, 8, 60)
+	a.Nil(err)
+	a.Equal([]uint64{0xEFFFFFFFFFFFFFFF, 0xF}, dst)
+
+	_, err = gobitstream.SetSigned64BitsFieldToSlice(dst, 128, 8, 0)
+	overflow, ok := err.(*gobitstream.FieldOverflowError)
+	a.True(ok)
+	a.Equal(uint64(8), overflow.Width)
+	a.True(overflow.Signed)
+	a.Equal(int64(128), int64(overflow.Value))
+
+	_, err = gobitstream.SetSigned64BitsFieldToSlice(dst, -129, 8, 0)
+	a.NotNil(err)
+
+	_, err = gobitstream.SetSigned64BitsFieldToSlice(dst, math.MinInt64, 64, 0)
+	a.Nil(err)
+}
+
+func TestReadWriteSigned(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	const loops = 500
+	widths := make([]int, loops)
+	values := make([]int64, loops)
+
+	totalBits := 0
+	for i := range widths {
+		widths[i] = r.Intn(64) + 1
+		totalBits += widths[i]
+		values[i] = int64(r.Uint64()) >> (64 - widths[i])
+	}
+
+	w := gobitstream.NewWriterLE(totalBits)
+	for i, width := range widths {
+		a.Nil(w.WriteNbitsFromInt64(width, values[i]))
+	}
+	a.Nil(w.Flush())
+
+	rd, err := gobitstream.NewReaderLE(totalBits, w.Bytes())
+	a.Nil(err)
+	for i, width := range widths {
+		v, err := rd.ReadNbitsInt64(width)
+		a.Nil(err)
+		a.Equal(values[i], v, "width: %d", width)
+	}
+}
+
+func TestWriteSignedOverflow(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewWriterLE(64)
+	a.Nil(w.WriteNbitsFromInt64(4, 3))
+
+	err := w.WriteNbitsFromInt64(13, 4096)
+	overflow, ok := err.(*gobitstream.FieldOverflowError)
+	if a.True(ok) {
+		a.Equal(uint64(4), overflow.Offset)
+		a.Equal(uint64(13), overflow.Width)
+		a.Equal("value 4096 does not fit in a signed 13 bits field at offset 4", overflow.Error())
+	}
+
+	a.Nil(w.WriteNbitsFromInt64(13, -4096))
+	a.NotNil(w.WriteNbitsFromInt64(65, 0))
+	a.NotNil(w.WriteNbitsFromInt64(0, 0))
+}
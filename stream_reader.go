@@ -0,0 +1,225 @@
+package gobitstream
+
+import (
+	"encoding/binary"
+	"io"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// defaultStreamBufferSize is the default size in bytes of the StreamReader word buffer.
+const defaultStreamBufferSize = 4096
+
+// maxConsecutiveEmptyReads is the number of (0, nil) reads tolerated before giving up with io.ErrNoProgress.
+const maxConsecutiveEmptyReads = 100
+
+// StreamReader is a bit stream reader that pulls its input from an io.Reader.
+// Only a window of the stream is kept in memory: the internal word buffer is refilled on demand,
+// so inputs larger than the available memory (captures, sockets) can be parsed.
+// Bits are consumed in the same order as a Reader created with NewReaderLE, or with NewReaderWithOrder
+// for a StreamReader created with NewStreamReaderWithOrder.
+type StreamReader struct {
+	src      io.Reader // Source of the bit stream
+	words    []uint64  // Buffered window of the bit stream
+	scratch  []byte    // Buffer used to read raw bytes from src
+	pos      int       // Current bit position within words
+	valid    int       // Number of valid bits within words
+	consumed int       // Number of bits dropped from the front of words
+	srcErr   error     // Sticky error returned by src
+	bitOrder BitOrder  // Order of the bits within each byte and field
+}
+
+// StreamReaderOption configures a StreamReader.
+type StreamReaderOption func(sr *StreamReader)
+
+// WithBufferSize sets the initial size in bytes of the StreamReader buffer.
+// The buffer still grows when a single read needs more bits than it can hold.
+func WithBufferSize(sizeInBytes int) StreamReaderOption {
+	return func(sr *StreamReader) {
+		if sizeInBytes < 8 {
+			sizeInBytes = 8
+		}
+		sr.words = make([]uint64, bitsToWordSize(sizeInBytes*8))
+	}
+}
+
+// NewStreamReader creates a new StreamReader that reads its bits from r.
+func NewStreamReader(r io.Reader, opts ...StreamReaderOption) *StreamReader {
+	sr := &StreamReader{src: r}
+	for _, opt := range opts {
+		opt(sr)
+	}
+	if sr.words == nil {
+		sr.words = make([]uint64, bitsToWordSize(defaultStreamBufferSize*8))
+	}
+	sr.scratch = make([]byte, len(sr.words)*8)
+	return sr
+}
+
+// NewStreamReaderWithOrder creates a new StreamReader that reads its bits from r with the specified bit order.
+// With MSBFirst the first bit read is the most significant bit of the values returned, as for NewReaderWithOrder.
+func NewStreamReaderWithOrder(r io.Reader, bitOrder BitOrder, opts ...StreamReaderOption) (*StreamReader, error) {
+	if err := checkOrders(LittleEndian, bitOrder); err != nil {
+		return nil, err
+	}
+	sr := NewStreamReader(r, opts...)
+	sr.bitOrder = bitOrder
+	return sr, nil
+}
+
+// Offset returns the number of bits consumed from the stream so far.
+func (sr *StreamReader) Offset() int { return sr.consumed + sr.pos }
+
+// fill makes sure at least nBits bits are buffered after the current position.
+// It returns io.EOF if the stream ended exactly at the current position, and
+// io.ErrUnexpectedEOF if it ended with fewer than nBits bits available.
+func (sr *StreamReader) fill(nBits int) error {
+	emptyReads := 0
+	for sr.valid-sr.pos < nBits {
+		if sr.srcErr != nil {
+			if sr.srcErr != io.EOF {
+				return sr.srcErr
+			}
+			if sr.valid == sr.pos {
+				return io.EOF
+			}
+			return io.ErrUnexpectedEOF
+		}
+
+		sr.compact()
+		sr.ensureCapacity(sr.pos + nBits)
+
+		free := len(sr.words)*8 - sr.valid/8
+		n, err := sr.src.Read(sr.scratch[:free])
+		sr.appendBytes(sr.scratch[:n])
+
+		if err != nil {
+			sr.srcErr = err
+		} else if n == 0 {
+			emptyReads++
+			if emptyReads >= maxConsecutiveEmptyReads {
+				return io.ErrNoProgress
+			}
+		} else {
+			emptyReads = 0
+		}
+	}
+	return nil
+}
+
+// compact drops the fully consumed words from the front of the buffer.
+func (sr *StreamReader) compact() {
+	drop := sr.pos / 64
+	if drop == 0 {
+		return
+	}
+	validWords := bitsToWordSize(sr.valid)
+	n := copy(sr.words, sr.words[drop:validWords])
+	for i := n; i < validWords; i++ {
+		sr.words[i] = 0
+	}
+	sr.pos -= drop * 64
+	sr.valid -= drop * 64
+	sr.consumed += drop * 64
+}
+
+// ensureCapacity grows the buffer so it can hold at least nBits bits.
+func (sr *StreamReader) ensureCapacity(nBits int) {
+	if len(sr.words)*64 >= nBits {
+		return
+	}
+	words := make([]uint64, bitsToWordSize(nBits)*2)
+	copy(words, sr.words)
+	sr.words = words
+	sr.scratch = make([]byte, len(sr.words)*8)
+}
+
+// appendBytes appends the bytes read from src to the buffered bits.
+func (sr *StreamReader) appendBytes(p []byte) {
+	for _, b := range p {
+		if sr.bitOrder == MSBFirst {
+			b = bits.Reverse8(b)
+		}
+		sr.words[sr.valid/64] |= uint64(b) << (sr.valid % 64)
+		sr.valid += 8
+	}
+}
+
+// checkNbits validates the requested number of bits.
+func checkNbits(nBits int) error {
+	if nBits <= 0 {
+		err := errors.Wrap(InvalidBitsSizeError, "nBits cannot be 0")
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// ReadNbitsUint64 reads nBits number of bits from the stream and returns the resulting uint64 value.
+// nBits must be between 1 and 64. If the stream ends before nBits bits are available, nothing is consumed and
+// io.EOF (no bits left) or io.ErrUnexpectedEOF (some bits left) is returned.
+func (sr *StreamReader) ReadNbitsUint64(nBits int) (res uint64, err error) {
+	if err = checkNbits(nBits); err != nil {
+		return 0, err
+	}
+	if nBits > 64 {
+		return 0, errors.Wrapf(InvalidBitsSizeError, "nBits: %d exceeds 64", nBits)
+	}
+	if err = sr.fill(nBits); err != nil {
+		return 0, err
+	}
+	res, err = Get64BitsFieldFromSlice(sr.words, uint64(nBits), uint64(sr.pos))
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	sr.pos += nBits
+	if sr.bitOrder == MSBFirst {
+		res = reverseField(res, nBits)
+	}
+	return res, nil
+}
+
+// ReadNbitsWords64 reads nBits number of bits from the stream and returns the resulting words as a slice of uint64 values.
+// The end of stream errors are the same as for ReadNbitsUint64.
+func (sr *StreamReader) ReadNbitsWords64(nBits int) (res []uint64, err error) {
+	res, err = sr.readRawWords(nBits)
+	if err == nil && sr.bitOrder == MSBFirst {
+		reverseFieldWords(res, nBits)
+	}
+	return res, err
+}
+
+// readRawWords reads nBits number of bits from the stream in stream order, whatever the bit order.
+func (sr *StreamReader) readRawWords(nBits int) (res []uint64, err error) {
+	if err = checkNbits(nBits); err != nil {
+		return nil, err
+	}
+	if err = sr.fill(nBits); err != nil {
+		return nil, err
+	}
+	res, err = GetFieldFromSlice(uint64(nBits), uint64(sr.pos), sr.words, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	sr.pos += nBits
+	return res, nil
+}
+
+// ReadNbitsBytes reads nBits number of bits from the stream and returns them as little-endian bytes.
+// With MSBFirst the first bit read is the most significant bit of the first byte.
+// The returned slice is newly allocated. The end of stream errors are the same as for ReadNbitsUint64.
+func (sr *StreamReader) ReadNbitsBytes(nBits int) (outBytes []byte, err error) {
+	words, err := sr.readRawWords(nBits)
+	if err != nil {
+		return nil, err
+	}
+	outBytes = make([]byte, 0, len(words)*8)
+	for _, word := range words {
+		outBytes = binary.LittleEndian.AppendUint64(outBytes, word)
+	}
+	outBytes = outBytes[:BitsToBytesSize(nBits)]
+	if sr.bitOrder == MSBFirst {
+		reverseBitsInBytes(outBytes)
+	}
+	return outBytes, nil
+}
@@ -0,0 +1,109 @@
+package gobitstream_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestStreamReaderMatchesReader(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	in := make([]byte, 2000)
+	r.Read(in)
+
+	rd, err := gobitstream.NewReaderLE(len(in)*8, in)
+	a.Nil(err)
+
+	srcs := map[string]io.Reader{
+		"plain":    bytes.NewReader(in),
+		"one byte": iotest.OneByteReader(bytes.NewReader(in)),
+		"half":     iotest.HalfReader(bytes.NewReader(in)),
+	}
+
+	for name, src := range srcs {
+		t.Run(name, func(t *testing.T) {
+			rd.Reset()
+			sr := gobitstream.NewStreamReader(src, gobitstream.WithBufferSize(16))
+			offset := 0
+			for {
+				width := r.Intn(200) + 1
+				if width > len(in)*8-offset {
+					break
+				}
+				offset += width
+				if width <= 64 {
+					expected, err := rd.ReadNbitsUint64(width)
+					a.Nil(err)
+					actual, err := sr.ReadNbitsUint64(width)
+					a.Nil(err)
+					a.Equal(expected, actual, "width: %d", width)
+				} else {
+					expected, err := rd.ReadNbitsWords64(width)
+					a.Nil(err)
+					actual, err := sr.ReadNbitsWords64(width)
+					a.Nil(err)
+					a.Equal(expected, actual, "width: %d", width)
+				}
+				a.Equal(offset, sr.Offset())
+			}
+		})
+	}
+}
+
+func TestStreamReaderBytes(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	sr := gobitstream.NewStreamReader(bytes.NewReader([]byte{0xAB, 0xCD, 0xEF}))
+	v, err := sr.ReadNbitsUint64(4)
+	a.Nil(err)
+	a.Equal(uint64(0xB), v)
+
+	out, err := sr.ReadNbitsBytes(12)
+	a.Nil(err)
+	a.Equal([]byte{0xDA, 0x0C}, out)
+}
+
+func TestStreamReaderEOF(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	sr := gobitstream.NewStreamReader(bytes.NewReader([]byte{0xFF, 0x01}))
+
+	_, err := sr.ReadNbitsUint64(12)
+	a.Nil(err)
+
+	_, err = sr.ReadNbitsUint64(8)
+	a.Equal(io.ErrUnexpectedEOF, err)
+
+	v, err := sr.ReadNbitsUint64(4)
+	a.Nil(err)
+	a.Equal(uint64(0), v)
+
+	_, err = sr.ReadNbitsUint64(1)
+	a.Equal(io.EOF, err)
+
+	_, err = sr.ReadNbitsWords64(100)
+	a.Equal(io.EOF, err)
+}
+
+func TestStreamReaderInvalidSize(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	sr := gobitstream.NewStreamReader(bytes.NewReader([]byte{0xFF}))
+	_, err := sr.ReadNbitsUint64(0)
+	a.NotNil(err)
+	_, err = sr.ReadNbitsUint64(65)
+	a.NotNil(err)
+}
+
+func TestStreamReaderSourceError(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	sr := gobitstream.NewStreamReader(iotest.ErrReader(iotest.ErrTimeout))
+	_, err := sr.ReadNbitsUint64(1)
+	a.Equal(iotest.ErrTimeout, err)
+}
@@ -0,0 +1,156 @@
+package gobitstream
+
+import (
+	"io"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// StreamWriter is a bit stream writer that pushes completed bytes to an io.Writer as soon as they are produced.
+// Only the trailing partial byte is kept in memory, so unbounded streams can be generated.
+// Bits are laid out in the same order as a Writer created with NewWriterLE, or with NewWriterWithOrder
+// for a StreamWriter created with NewStreamWriterWithOrder.
+type StreamWriter struct {
+	dst      io.Writer // Destination of the completed bytes
+	acc      uint64    // Accumulator holding the bits not yet emitted
+	accBits  int       // Number of valid bits in acc, always below 8 between calls
+	offset   int       // Number of bits written so far
+	pending  []byte    // Completed bytes waiting to be written to dst
+	err      error     // Sticky error returned by dst
+	closed   bool      // Whether Close has been called
+	bitOrder BitOrder  // Order of the bits within each byte and field
+}
+
+// NewStreamWriter creates a new StreamWriter that writes its bytes to w.
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return &StreamWriter{dst: w, pending: make([]byte, 0, 64)}
+}
+
+// NewStreamWriterWithOrder creates a new StreamWriter that writes its bytes to w with the specified bit order.
+// With MSBFirst values are written most significant bit first, as for NewWriterWithOrder.
+func NewStreamWriterWithOrder(w io.Writer, bitOrder BitOrder) (*StreamWriter, error) {
+	if err := checkOrders(LittleEndian, bitOrder); err != nil {
+		return nil, err
+	}
+	sw := NewStreamWriter(w)
+	sw.bitOrder = bitOrder
+	return sw, nil
+}
+
+// Offset returns the number of bits written to the stream so far.
+func (sw *StreamWriter) Offset() int { return sw.offset }
+
+// checkWritable returns an error if the StreamWriter can no longer accept bits.
+func (sw *StreamWriter) checkWritable() error {
+	if sw.err != nil {
+		return sw.err
+	}
+	if sw.closed {
+		return errors.New("write to closed StreamWriter")
+	}
+	return nil
+}
+
+// push adds up to 56 bits to the accumulator and moves every completed byte to pending.
+func (sw *StreamWriter) push(nBits int, val uint64) {
+	sw.acc |= (val & (1<<nBits - 1)) << sw.accBits
+	sw.accBits += nBits
+	for sw.accBits >= 8 {
+		sw.pending = append(sw.pending, byte(sw.acc))
+		sw.acc >>= 8
+		sw.accBits -= 8
+	}
+}
+
+// emit writes the pending completed bytes to the destination.
+func (sw *StreamWriter) emit() error {
+	if len(sw.pending) == 0 {
+		return nil
+	}
+	if sw.bitOrder == MSBFirst {
+		reverseBitsInBytes(sw.pending)
+	}
+	_, err := sw.dst.Write(sw.pending)
+	sw.pending = sw.pending[:0]
+	if err != nil {
+		sw.err = err
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// WriteNbitsFromWord writes nBits bits of val to the stream and emits the bytes it completes.
+// nBits must be between 1 and 64.
+func (sw *StreamWriter) WriteNbitsFromWord(nBits int, val uint64) error {
+	if err := checkNbits(nBits); err != nil {
+		return err
+	}
+	if nBits > 64 {
+		return errors.New("invalid number of bits: exceeds 64")
+	}
+	if err := sw.checkWritable(); err != nil {
+		return err
+	}
+	if sw.bitOrder == MSBFirst {
+		val = reverseField(val, nBits)
+	}
+
+	for remaining := nBits; remaining > 0; {
+		chunk := remaining
+		if chunk > 56 {
+			chunk = 56
+		}
+		sw.push(chunk, val)
+		val >>= chunk
+		remaining -= chunk
+	}
+	sw.offset += nBits
+	return sw.emit()
+}
+
+// WriteNbitsFromBytes writes nBits bits taken from the little-endian byte slice val to the stream
+// and emits the bytes it completes.
+func (sw *StreamWriter) WriteNbitsFromBytes(nBits int, val []byte) error {
+	if err := checkNbits(nBits); err != nil {
+		return err
+	}
+	if err := checkByteSize(BitsToBytesSize(nBits), len(val)); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := sw.checkWritable(); err != nil {
+		return err
+	}
+
+	for remaining := nBits; remaining > 0; {
+		chunk := remaining
+		if chunk > 8 {
+			chunk = 8
+		}
+		b := val[0]
+		if sw.bitOrder == MSBFirst {
+			b = bits.Reverse8(b)
+		}
+		sw.push(chunk, uint64(b))
+		val = val[1:]
+		remaining -= chunk
+	}
+	sw.offset += nBits
+	return sw.emit()
+}
+
+// Close pads the trailing partial byte with zeros and writes it to the destination.
+// It does not close the underlying io.Writer. Further writes return an error.
+func (sw *StreamWriter) Close() error {
+	if sw.closed {
+		return sw.err
+	}
+	if sw.err != nil {
+		return sw.err
+	}
+	if sw.accBits > 0 {
+		sw.push(8-sw.accBits, 0)
+	}
+	sw.closed = true
+	return sw.emit()
+}
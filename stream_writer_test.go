@@ -0,0 +1,73 @@
+package gobitstream_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/iotest"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestStreamWriterMatchesWriter(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	const totalBits = 5000
+	w := gobitstream.NewWriterLE(totalBits)
+	var out bytes.Buffer
+	sw := gobitstream.NewStreamWriter(&out)
+
+	for offset := 0; offset < totalBits; {
+		width := r.Intn(64) + 1
+		if width > totalBits-offset {
+			width = totalBits - offset
+		}
+		val := r.Uint64()
+		a.Nil(w.WriteNbitsFromWord(width, val))
+		a.Nil(sw.WriteNbitsFromWord(width, val))
+		offset += width
+
+		a.Equal(offset, sw.Offset())
+		a.Equal(offset/8, out.Len())
+	}
+
+	a.Nil(w.Flush())
+	a.Nil(sw.Close())
+	a.Equal(w.Bytes(), out.Bytes())
+}
+
+func TestStreamWriterBytes(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	var out bytes.Buffer
+	sw := gobitstream.NewStreamWriter(&out)
+
+	a.Nil(sw.WriteNbitsFromWord(4, 0xB))
+	a.Nil(sw.WriteNbitsFromBytes(12, []byte{0xDA, 0x0C}))
+	a.Equal([]byte{0xAB, 0xCD}, out.Bytes())
+
+	a.Nil(sw.WriteNbitsFromWord(1, 1))
+	a.Nil(sw.Close())
+	a.Equal([]byte{0xAB, 0xCD, 0x01}, out.Bytes())
+
+	a.NotNil(sw.WriteNbitsFromWord(1, 1))
+}
+
+func TestStreamWriterErrors(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	sw := gobitstream.NewStreamWriter(&bytes.Buffer{})
+	a.NotNil(sw.WriteNbitsFromWord(0, 0))
+	a.NotNil(sw.WriteNbitsFromWord(65, 0))
+	a.NotNil(sw.WriteNbitsFromBytes(9, []byte{0xFF}))
+
+	sw = gobitstream.NewStreamWriter(failingWriter{})
+	a.Nil(sw.WriteNbitsFromWord(7, 0))
+	a.NotNil(sw.WriteNbitsFromWord(7, 0))
+	a.NotNil(sw.WriteNbitsFromWord(7, 0))
+	a.NotNil(sw.Close())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, iotest.ErrTimeout }
@@ -0,0 +1,74 @@
+package gobitstream
+
+// fitsUnsigned reports whether val can be represented as an unsigned value of widthInBits bits.
+func fitsUnsigned(val, widthInBits uint64) bool {
+	return widthInBits >= 64 || val>>widthInBits == 0
+}
+
+// wordsOverflow returns a *FieldOverflowError if any bit of field past widthInBits is set, nil otherwise.
+func wordsOverflow(field []uint64, widthInBits, offsetInBits uint64) error {
+	index := widthInBits / 64
+	for i := index; i < uint64(len(field)); i++ {
+		word := field[i]
+		if i == index {
+			word >>= widthInBits % 64
+		}
+		if word != 0 {
+			return &FieldOverflowError{Offset: offsetInBits, Width: widthInBits, Value: field[0]}
+		}
+	}
+	return nil
+}
+
+// Set64BitsFieldToSliceStrict works like Set64BitsFieldToSlice, but instead of masking the input it returns
+// a *FieldOverflowError if inputField does not fit in widthInBits bits.
+func Set64BitsFieldToSliceStrict(destinationField []uint64, inputField, widthInBits, offsetInBits uint64) ([]uint64, error) {
+	if widthInBits > 0 && !fitsUnsigned(inputField, widthInBits) {
+		return nil, &FieldOverflowError{Offset: offsetInBits, Width: widthInBits, Value: inputField}
+	}
+	return Set64BitsFieldToSlice(destinationField, inputField, widthInBits, offsetInBits)
+}
+
+// SetFieldToSliceStrict works like SetFieldToSlice, but instead of ignoring the bits of field past widthInBits
+// it returns a *FieldOverflowError if any of them is set. The Value of the error holds the low 64 bits of field.
+// Words of field past widthInBits are accepted as long as they are zero.
+func SetFieldToSliceStrict(dstSlice []uint64, field []uint64, widthInBits, offsetInBits uint64) ([]uint64, error) {
+	if widthInBits > 0 {
+		if err := wordsOverflow(field, widthInBits, offsetInBits); err != nil {
+			return nil, err
+		}
+		if n := bitsToWordSize(int(widthInBits)); n < len(field) {
+			field = field[:n]
+		}
+	}
+	return SetFieldToSlice(dstSlice, field, widthInBits, offsetInBits)
+}
+
+// SetStrict enables or disables the strict mode of the Writer. By default values wider than the field they are
+// written to are masked to the field width. In strict mode they are rejected with a *FieldOverflowError naming
+// the offset, the width and the offending value, and nothing is written.
+// Signed values written with WriteNbitsFromInt64 are always checked as two's complement values.
+func (wr *Writer) SetStrict(strict bool) {
+	wr.strict = strict
+}
+
+// Strict reports whether the Writer is in strict mode.
+func (wr *Writer) Strict() bool {
+	return wr.strict
+}
+
+// checkStrict returns a *FieldOverflowError in strict mode if val does not fit in nBits bits.
+func (wr *Writer) checkStrict(nBits int, val uint64) error {
+	if wr.strict && nBits > 0 && !fitsUnsigned(val, uint64(nBits)) {
+		return &FieldOverflowError{Offset: uint64(wr.offset), Width: uint64(nBits), Value: val}
+	}
+	return nil
+}
+
+// checkStrictWords returns a *FieldOverflowError in strict mode if any bit of words past nBits is set.
+func (wr *Writer) checkStrictWords(nBits int, words []uint64) error {
+	if !wr.strict {
+		return nil
+	}
+	return wordsOverflow(words, uint64(nBits), uint64(wr.offset))
+}
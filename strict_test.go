@@ -0,0 +1,159 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestSet64BitsFieldToSliceStrict(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		value    uint64
+		width    uint64
+		offset   uint64
+		overflow bool
+	}{
+		{name: "fits", value: 255, width: 8, offset: 4},
+		{name: "one bit too wide", value: 256, width: 8, offset: 4, overflow: true},
+		{name: "300 in 8 bits", value: 300, width: 8, offset: 60, overflow: true},
+		{name: "full word", value: 0xFFFFFFFFFFFFFFFF, width: 64, offset: 0},
+		{name: "single bit", value: 2, width: 1, offset: 3, overflow: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := []uint64{0x1111, 0x2222}
+			out, err := gobitstream.Set64BitsFieldToSliceStrict(dst, tc.value, tc.width, tc.offset)
+			if !tc.overflow {
+				a.Nil(err)
+				expected, err := gobitstream.Set64BitsFieldToSlice([]uint64{0x1111, 0x2222}, tc.value, tc.width, tc.offset)
+				a.Nil(err)
+				a.Equal(expected, out)
+				return
+			}
+			overflow, ok := err.(*gobitstream.FieldOverflowError)
+			a.True(ok)
+			a.Equal(&gobitstream.FieldOverflowError{Offset: tc.offset, Width: tc.width, Value: tc.value}, overflow)
+			a.Nil(out)
+			a.Equal([]uint64{0x1111, 0x2222}, dst)
+		})
+	}
+}
+
+func TestSetFieldToSliceStrict(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	testCases := []struct {
+		name     string
+		field    []uint64
+		width    uint64
+		overflow bool
+	}{
+		{name: "fits", field: []uint64{0xFFFFFFFFFFFFFFFF, 0x3F}, width: 70},
+		{name: "top word too wide", field: []uint64{0x1, 0x40}, width: 70, overflow: true},
+		{name: "extra word set", field: []uint64{0x1, 0x0, 0x1}, width: 128, overflow: true},
+		{name: "extra word clear", field: []uint64{0x1, 0x0, 0x0}, width: 128},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := make([]uint64, 4)
+			out, err := gobitstream.SetFieldToSliceStrict(dst, tc.field, tc.width, 10)
+			if !tc.overflow {
+				a.Nil(err)
+				a.NotNil(out)
+				return
+			}
+			overflow, ok := err.(*gobitstream.FieldOverflowError)
+			a.True(ok)
+			a.Equal(uint64(10), overflow.Offset)
+			a.Equal(tc.width, overflow.Width)
+			a.Equal(tc.field[0], overflow.Value)
+			a.Equal(make([]uint64, 4), dst)
+		})
+	}
+}
+
+func TestWriterStrictMode(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewWriterLE(64)
+	a.Nil(w.WriteNbitsFromWord(8, 300))
+	a.Equal(uint64(44), w.Uint64())
+
+	w = gobitstream.NewWriterLE(256)
+	a.False(w.Strict())
+	w.SetStrict(true)
+	a.True(w.Strict())
+	a.Nil(w.WriteNbitsFromWord(3, 0x5))
+
+	err := w.WriteNbitsFromWord(8, 300)
+	overflow, ok := err.(*gobitstream.FieldOverflowError)
+	a.True(ok)
+	a.Equal(&gobitstream.FieldOverflowError{Offset: 3, Width: 8, Value: 300}, overflow)
+	a.Equal("value 300 does not fit in an unsigned 8 bits field at offset 3", err.Error())
+	a.Equal(3, w.Reader().Remaining())
+
+	_, ok = w.WriteNbitsFromInt64(8, 200).(*gobitstream.FieldOverflowError)
+	a.True(ok)
+	a.Nil(w.WriteNbitsFromInt64(8, -1))
+
+	_, ok = w.WriteNbitsFromBytes(12, []byte{0xFF, 0x1F}).(*gobitstream.FieldOverflowError)
+	a.True(ok)
+	_, ok = w.WriteNbitsFromBytes(8, []byte{0xFF, 0x01}).(*gobitstream.FieldOverflowError)
+	a.True(ok)
+	a.Nil(w.WriteNbitsFromBytes(12, []byte{0xFF, 0x0F}))
+
+	_, ok = w.WriteUint128(64, gobitstream.Uint128{Hi: 1}).(*gobitstream.FieldOverflowError)
+	a.True(ok)
+	_, ok = w.WriteGray(4, 16).(*gobitstream.FieldOverflowError)
+	a.True(ok)
+	a.Equal(23, w.Reader().Remaining())
+
+	r, err := w.Reserve(4)
+	a.Nil(err)
+	_, ok = r.Set(16).(*gobitstream.FieldOverflowError)
+	a.True(ok)
+	a.Nil(r.Set(15))
+}
+
+func TestWriterStrictModeEncoders(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+		var outputs [][]byte
+		for _, strict := range []bool{false, true} {
+			w, err := gobitstream.NewWriterWithOrder(512, gobitstream.BigEndian, bitOrder)
+			a.Nil(err)
+			w.SetStrict(strict)
+			a.Nil(w.WriteUE(1000))
+			a.Nil(w.WriteSE(-5))
+			a.Nil(w.WriteRice(3, 77))
+			a.Nil(w.WriteGolomb(10, 123))
+			a.Nil(w.WriteUnary(70, 1))
+			a.Nil(w.WriteUvarint(1 << 40))
+			a.Nil(w.WriteVarint(-300))
+			_, err = w.AlignTo(8, gobitstream.PadOnes)
+			a.Nil(err)
+			a.Nil(w.Flush())
+			outputs = append(outputs, append([]byte(nil), w.Bytes()...))
+		}
+		a.Equal(outputs[0], outputs[1])
+	}
+}
+
+func TestWriterStrictModeErrorsAreTyped(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewGrowableWriterBE()
+	w.SetStrict(true)
+	err := w.WriteNbitsFromWord(1, 2)
+	a.Equal(err, errors.Cause(err))
+	a.Equal(0, len(w.Words()))
+}
@@ -0,0 +1,229 @@
+package gobitstream
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// Uint128 is an unsigned 128 bits integer. Arithmetic wraps around modulo 2^128, as it does for the built-in types.
+type Uint128 struct {
+	Hi uint64
+	Lo uint64
+}
+
+// Uint128FromUint64 returns v as a Uint128.
+func Uint128FromUint64(v uint64) Uint128 {
+	return Uint128{Lo: v}
+}
+
+// Add returns x + y.
+func (x Uint128) Add(y Uint128) Uint128 {
+	lo, carry := bits.Add64(x.Lo, y.Lo, 0)
+	hi, _ := bits.Add64(x.Hi, y.Hi, carry)
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+// Sub returns x - y.
+func (x Uint128) Sub(y Uint128) Uint128 {
+	lo, borrow := bits.Sub64(x.Lo, y.Lo, 0)
+	hi, _ := bits.Sub64(x.Hi, y.Hi, borrow)
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+// Mul returns x * y.
+func (x Uint128) Mul(y Uint128) Uint128 {
+	hi, lo := bits.Mul64(x.Lo, y.Lo)
+	hi += x.Hi*y.Lo + x.Lo*y.Hi
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+// QuoRem64 returns the quotient and the remainder of x / y. It panics if y is zero.
+func (x Uint128) QuoRem64(y uint64) (Uint128, uint64) {
+	hi, rem := bits.Div64(0, x.Hi, y)
+	lo, rem := bits.Div64(rem, x.Lo, y)
+	return Uint128{Hi: hi, Lo: lo}, rem
+}
+
+// Lsh returns x << n.
+func (x Uint128) Lsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{Hi: x.Lo << (n - 64)}
+	}
+	return Uint128{Hi: x.Hi<<n | x.Lo>>(64-n), Lo: x.Lo << n}
+}
+
+// Rsh returns x >> n.
+func (x Uint128) Rsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{Lo: x.Hi >> (n - 64)}
+	}
+	return Uint128{Hi: x.Hi >> n, Lo: x.Lo>>n | x.Hi<<(64-n)}
+}
+
+// And returns x & y.
+func (x Uint128) And(y Uint128) Uint128 {
+	return Uint128{Hi: x.Hi & y.Hi, Lo: x.Lo & y.Lo}
+}
+
+// Or returns x | y.
+func (x Uint128) Or(y Uint128) Uint128 {
+	return Uint128{Hi: x.Hi | y.Hi, Lo: x.Lo | y.Lo}
+}
+
+// Xor returns x ^ y.
+func (x Uint128) Xor(y Uint128) Uint128 {
+	return Uint128{Hi: x.Hi ^ y.Hi, Lo: x.Lo ^ y.Lo}
+}
+
+// Not returns ^x.
+func (x Uint128) Not() Uint128 {
+	return Uint128{Hi: ^x.Hi, Lo: ^x.Lo}
+}
+
+// Cmp compares x and y and returns -1, 0 or +1.
+func (x Uint128) Cmp(y Uint128) int {
+	switch {
+	case x.Hi < y.Hi || (x.Hi == y.Hi && x.Lo < y.Lo):
+		return -1
+	case x == y:
+		return 0
+	}
+	return 1
+}
+
+// IsZero reports whether x is zero.
+func (x Uint128) IsZero() bool {
+	return x.Hi == 0 && x.Lo == 0
+}
+
+// Len returns the minimum number of bits required to represent x.
+func (x Uint128) Len() int {
+	if x.Hi != 0 {
+		return 64 + bits.Len64(x.Hi)
+	}
+	return bits.Len64(x.Lo)
+}
+
+// Big returns x as a big.Int.
+func (x Uint128) Big() *big.Int {
+	v := new(big.Int).SetUint64(x.Hi)
+	return v.Lsh(v, 64).Or(v, new(big.Int).SetUint64(x.Lo))
+}
+
+// Text returns the representation of x in the given base, which must be between 2 and 36.
+func (x Uint128) Text(base int) string {
+	if base < 2 || base > 36 {
+		panic("gobitstream: invalid base")
+	}
+	const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+	var buf [128]byte
+	i := len(buf)
+	for {
+		var digit uint64
+		x, digit = x.QuoRem64(uint64(base))
+		i--
+		buf[i] = digits[digit]
+		if x.IsZero() {
+			return string(buf[i:])
+		}
+	}
+}
+
+// String returns the decimal representation of x.
+func (x Uint128) String() string {
+	return x.Text(10)
+}
+
+// check128Bits validates a field of up to 128 bits against the size of a slice of words.
+func check128Bits(sliceLen int, widthInBits, offsetInBits uint64) error {
+	if widthInBits == 0 || widthInBits > 128 {
+		return errors.Wrapf(InvalidWidthError, "width must be between 1 and 128, got: %d", widthInBits)
+	}
+	if offsetInBits+widthInBits > uint64(sliceLen)*64 {
+		return errors.Wrapf(OffsetOutOfRangeError, "width: %d, offset: %d, slice words: %d", widthInBits, offsetInBits, sliceLen)
+	}
+	return nil
+}
+
+// Get128BitsFieldFromSlice extracts a field of up to 128 bits from a slice of uint64, as Get64BitsFieldFromSlice
+// does for fields of up to 64 bits.
+func Get128BitsFieldFromSlice(inputFieldSlice []uint64, widthInBits, offsetInBits uint64) (Uint128, error) {
+	if err := check128Bits(len(inputFieldSlice), widthInBits, offsetInBits); err != nil {
+		return Uint128{}, err
+	}
+	if widthInBits <= 64 {
+		lo, err := Get64BitsFieldFromSlice(inputFieldSlice, widthInBits, offsetInBits)
+		return Uint128{Lo: lo}, err
+	}
+	lo, err := Get64BitsFieldFromSlice(inputFieldSlice, 64, offsetInBits)
+	if err != nil {
+		return Uint128{}, err
+	}
+	hi, err := Get64BitsFieldFromSlice(inputFieldSlice, widthInBits-64, offsetInBits+64)
+	if err != nil {
+		return Uint128{}, err
+	}
+	return Uint128{Hi: hi, Lo: lo}, nil
+}
+
+// Set128BitsFieldToSlice sets a field of up to 128 bits in a slice of uint64, as Set64BitsFieldToSlice
+// does for fields of up to 64 bits. The bits of inputField above widthInBits are ignored.
+func Set128BitsFieldToSlice(destinationField []uint64, inputField Uint128, widthInBits, offsetInBits uint64) ([]uint64, error) {
+	if err := check128Bits(len(destinationField), widthInBits, offsetInBits); err != nil {
+		return nil, err
+	}
+	if widthInBits <= 64 {
+		return Set64BitsFieldToSlice(destinationField, inputField.Lo, widthInBits, offsetInBits)
+	}
+	dst, err := Set64BitsFieldToSlice(destinationField, inputField.Lo, 64, offsetInBits)
+	if err != nil {
+		return nil, err
+	}
+	return Set64BitsFieldToSlice(dst, inputField.Hi, widthInBits-64, offsetInBits+64)
+}
+
+// ReadUint128 reads nBits number of bits, up to 128, from the bit stream and returns them as a Uint128.
+// With MSBFirst bit order the first bit read is the most significant bit of the result.
+func (wr *Reader) ReadUint128(nBits int) (res Uint128, err error) {
+	if nBits > 128 {
+		return res, errors.Wrapf(InvalidBitsSizeError, "nBits: %d exceeds 128", nBits)
+	}
+	if err = wr.checkNbitsSize(nBits); err != nil {
+		return res, err
+	}
+	if nBits <= 64 {
+		res.Lo, err = wr.ReadNbitsUint64(nBits)
+		return res, err
+	}
+	first, second := &res.Lo, &res.Hi
+	firstBits, secondBits := 64, nBits-64
+	if wr.bitOrder == MSBFirst {
+		first, second = second, first
+		firstBits, secondBits = secondBits, firstBits
+	}
+	if *first, err = wr.ReadNbitsUint64(firstBits); err != nil {
+		return Uint128{}, err
+	}
+	if *second, err = wr.ReadNbitsUint64(secondBits); err != nil {
+		wr.offset -= firstBits
+		return Uint128{}, err
+	}
+	return res, nil
+}
+
+// WriteUint128 writes the nBits low bits of val, up to 128, to the bit stream.
+// With MSBFirst bit order the most significant bit of the field is written first.
+func (wr *Writer) WriteUint128(nBits int, val Uint128) error {
+	if nBits > 128 {
+		return errors.Wrapf(InvalidBitsSizeError, "nBits: %d exceeds 128", nBits)
+	}
+	return wr.writeNbitsFromWords(nBits, []uint64{val.Lo, val.Hi})
+}
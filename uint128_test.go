@@ -0,0 +1,134 @@
+package gobitstream_test
+
+import (
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestUint128Arithmetic(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	modulus := new(big.Int).Lsh(big.NewInt(1), 128)
+	wrap := func(v *big.Int) *big.Int {
+		return v.Mod(v, modulus)
+	}
+	equal := func(expected, actual *big.Int) {
+		a.Equal(0, expected.Cmp(actual), "expected: %s, actual: %s", expected, actual)
+	}
+
+	for i := 0; i < 1000; i++ {
+		x := gobitstream.Uint128{Hi: r.Uint64(), Lo: r.Uint64()}
+		y := gobitstream.Uint128{Hi: r.Uint64() >> uint(r.Intn(64)), Lo: r.Uint64()}
+		n := uint(r.Intn(130))
+		d := r.Uint64() | 1
+		xb, yb := x.Big(), y.Big()
+
+		equal(wrap(new(big.Int).Add(xb, yb)), x.Add(y).Big())
+		equal(wrap(new(big.Int).Sub(xb, yb)), x.Sub(y).Big())
+		equal(wrap(new(big.Int).Mul(xb, yb)), x.Mul(y).Big())
+		equal(wrap(new(big.Int).Lsh(xb, n)), x.Lsh(n).Big())
+		equal(new(big.Int).Rsh(xb, n), x.Rsh(n).Big())
+		equal(new(big.Int).And(xb, yb), x.And(y).Big())
+		equal(new(big.Int).Or(xb, yb), x.Or(y).Big())
+		equal(new(big.Int).Xor(xb, yb), x.Xor(y).Big())
+		a.Equal(xb.Cmp(yb), x.Cmp(y))
+		a.Equal(xb.BitLen(), x.Len())
+		a.Equal(xb.Text(10), x.String())
+		a.Equal(xb.Text(16), x.Text(16))
+
+		q, rem := x.QuoRem64(d)
+		qb, remb := new(big.Int).QuoRem(xb, new(big.Int).SetUint64(d), new(big.Int))
+		equal(qb, q.Big())
+		a.Equal(remb.Uint64(), rem)
+	}
+
+	maxValue := gobitstream.Uint128{}.Not()
+	a.Equal("340282366920938463463374607431768211455", maxValue.String())
+	a.True(maxValue.Add(gobitstream.Uint128FromUint64(1)).IsZero())
+	a.Equal(0, maxValue.Cmp(gobitstream.Uint128{}.Sub(gobitstream.Uint128FromUint64(1))))
+	a.Equal("0", gobitstream.Uint128{}.String())
+	a.Equal(0, gobitstream.Uint128{}.Len())
+}
+
+func TestGetSet128BitsFieldFromSlice(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	for i := 0; i < 1000; i++ {
+		width := uint64(r.Intn(128) + 1)
+		offset := uint64(r.Intn(3*64 - int(width) + 1))
+		val := gobitstream.Uint128{Hi: r.Uint64(), Lo: r.Uint64()}
+		masked := val.And(gobitstream.Uint128{}.Not().Rsh(uint(128 - width)))
+
+		dst := []uint64{r.Uint64(), r.Uint64(), r.Uint64()}
+		expected, err := gobitstream.SetBigIntFieldToSlice(append([]uint64(nil), dst...), masked.Big(), width, offset)
+		a.Nil(err)
+		dst, err = gobitstream.Set128BitsFieldToSlice(dst, val, width, offset)
+		a.Nil(err)
+		a.Equal(expected, dst)
+
+		actual, err := gobitstream.Get128BitsFieldFromSlice(dst, width, offset)
+		a.Nil(err)
+		a.Equal(masked, actual)
+	}
+
+	_, err := gobitstream.Get128BitsFieldFromSlice(make([]uint64, 2), 129, 0)
+	a.Equal(gobitstream.InvalidWidthError, errors.Cause(err))
+	_, err = gobitstream.Get128BitsFieldFromSlice(make([]uint64, 2), 128, 1)
+	a.Equal(gobitstream.OffsetOutOfRangeError, errors.Cause(err))
+
+	// A field that does not fit leaves the slice untouched.
+	dst := []uint64{0, 0}
+	_, err = gobitstream.Set128BitsFieldToSlice(dst, gobitstream.Uint128{}.Not(), 100, 30)
+	a.NotNil(err)
+	a.Equal([]uint64{0, 0}, dst)
+}
+
+func TestReadWriteUint128(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	ipv6 := gobitstream.Uint128{Hi: 0x20010DB800000000, Lo: 0x0000000000000001}
+	timestamp := gobitstream.Uint128{Hi: 0x12345678, Lo: 0x9ABCDEF012345678}
+
+	for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+		for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+			w, err := gobitstream.NewWriterWithOrder(3+128+96+40, byteOrder, bitOrder)
+			a.Nil(err)
+			a.Nil(w.WriteNbitsFromWord(3, 0x5))
+			a.Nil(w.WriteUint128(128, ipv6))
+			a.Nil(w.WriteUint128(96, timestamp))
+			a.Nil(w.WriteUint128(40, gobitstream.Uint128FromUint64(0xAB12345678)))
+			a.NotNil(w.WriteUint128(129, ipv6))
+
+			rd := w.Reader()
+			_, err = rd.ReadNbitsUint64(3)
+			a.Nil(err)
+			actual, err := rd.ReadUint128(128)
+			a.Nil(err)
+			a.Equal(ipv6, actual)
+			actual, err = rd.ReadUint128(96)
+			a.Nil(err)
+			a.Equal(timestamp, actual)
+
+			offset := rd.Offset()
+			actual, err = rd.ReadUint128(40)
+			a.Nil(err)
+			a.Equal(gobitstream.Uint128FromUint64(0xAB12345678), actual)
+			_, err = rd.ReadUint128(1)
+			a.NotNil(err)
+			// Matches the big.Int accessors in every bit order.
+			_, err = rd.SeekBit(int64(offset-96), io.SeekStart)
+			a.Nil(err)
+			asBig, err := rd.ReadBigInt(96, false)
+			a.Nil(err)
+			a.Equal(0, timestamp.Big().Cmp(asBig))
+			_, err = rd.ReadUint128(129)
+			a.NotNil(err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package gobitstream
+
+import (
+	"github.com/pkg/errors"
+)
+
+// LEB128 / protobuf style varints at any bit alignment.
+//
+// A varint is a sequence of groups of groupBits+1 bits. Each group is read as one field whose low groupBits bits
+// are the payload and whose top bit is set when another group follows. Payloads are stored least significant
+// group first. With 7 bits groups on a byte aligned little-endian stream this is the encoding of
+// encoding/binary.PutUvarint.
+
+// DefaultVarintGroupBits is the payload size of the groups of a standard LEB128 varint.
+const DefaultVarintGroupBits = 7
+
+// checkVarintGroup validates the payload size of the varint groups.
+func checkVarintGroup(groupBits int) error {
+	if groupBits < 1 || groupBits > 63 {
+		return errors.Wrapf(InvalidWidthError, "varint group must be between 1 and 63 bits, got: %d", groupBits)
+	}
+	return nil
+}
+
+// zigzagEncode maps signed values to unsigned ones so small magnitudes stay small: 0, -1, 1, -2... to 0, 1, 2, 3...
+func zigzagEncode(val int64) uint64 {
+	return uint64(val<<1) ^ uint64(val>>63)
+}
+
+// zigzagDecode reverses zigzagEncode.
+func zigzagDecode(val uint64) int64 {
+	return int64(val>>1) ^ -int64(val&1)
+}
+
+// ReadUvarintGroup reads an unsigned varint made of groups with groupBits bits of payload.
+// A CodeOverflowError is returned if the value does not fit in 64 bits, and a TruncatedCodeError if the bit stream
+// ends in the middle of the varint. On error nothing is consumed.
+func (wr *Reader) ReadUvarintGroup(groupBits int) (uint64, error) {
+	if err := checkVarintGroup(groupBits); err != nil {
+		return 0, err
+	}
+	start := wr.offset
+	var val uint64
+	for shift := 0; ; shift += groupBits {
+		if shift >= 64 {
+			wr.offset = start
+			return 0, errors.Wrapf(CodeOverflowError, "varint at offset: %d", start)
+		}
+		group, err := wr.ReadNbitsUint64(groupBits + 1)
+		if err != nil {
+			wr.offset = start
+			return 0, errors.Wrapf(TruncatedCodeError, "varint at offset: %d", start)
+		}
+		payload := group & (1<<groupBits - 1)
+		if shift > 0 && payload>>(64-shift) != 0 {
+			wr.offset = start
+			return 0, errors.Wrapf(CodeOverflowError, "varint at offset: %d", start)
+		}
+		val |= payload << shift
+		if group>>groupBits == 0 {
+			return val, nil
+		}
+	}
+}
+
+// ReadVarintGroup reads a zigzag encoded signed varint made of groups with groupBits bits of payload.
+func (wr *Reader) ReadVarintGroup(groupBits int) (int64, error) {
+	val, err := wr.ReadUvarintGroup(groupBits)
+	if err != nil {
+		return 0, err
+	}
+	return zigzagDecode(val), nil
+}
+
+// ReadUvarint reads a standard LEB128 unsigned varint, starting at the current offset even if it is not byte aligned.
+func (wr *Reader) ReadUvarint() (uint64, error) {
+	return wr.ReadUvarintGroup(DefaultVarintGroupBits)
+}
+
+// ReadVarint reads a standard zigzag encoded signed varint, starting at the current offset even if it is not byte aligned.
+func (wr *Reader) ReadVarint() (int64, error) {
+	return wr.ReadVarintGroup(DefaultVarintGroupBits)
+}
+
+// WriteUvarintGroup writes val as an unsigned varint made of groups with groupBits bits of payload.
+// On error the offset is restored to where the varint starts.
+func (wr *Writer) WriteUvarintGroup(groupBits int, val uint64) error {
+	if err := checkVarintGroup(groupBits); err != nil {
+		return err
+	}
+	start := wr.offset
+	for {
+		group := val & (1<<groupBits - 1)
+		val >>= groupBits
+		if val != 0 {
+			group |= 1 << groupBits
+		}
+		if err := wr.WriteNbitsFromWord(groupBits+1, group); err != nil {
+			wr.offset = start
+			return err
+		}
+		if val == 0 {
+			return nil
+		}
+	}
+}
+
+// WriteVarintGroup writes val as a zigzag encoded signed varint made of groups with groupBits bits of payload.
+func (wr *Writer) WriteVarintGroup(groupBits int, val int64) error {
+	return wr.WriteUvarintGroup(groupBits, zigzagEncode(val))
+}
+
+// WriteUvarint writes val as a standard LEB128 unsigned varint at the current offset.
+func (wr *Writer) WriteUvarint(val uint64) error {
+	return wr.WriteUvarintGroup(DefaultVarintGroupBits, val)
+}
+
+// WriteVarint writes val as a standard zigzag encoded signed varint at the current offset.
+func (wr *Writer) WriteVarint(val int64) error {
+	return wr.WriteVarintGroup(DefaultVarintGroupBits, val)
+}
@@ -0,0 +1,115 @@
+package gobitstream_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+	"github.com/pkg/errors"
+)
+
+func TestVarintMatchesEncodingBinary(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	values := []uint64{0, 1, 127, 128, 300, math.MaxUint32, math.MaxUint64}
+	for i := 0; i < 100; i++ {
+		values = append(values, r.Uint64()>>uint(r.Intn(64)))
+	}
+
+	for _, val := range values {
+		expected := binary.AppendUvarint(nil, val)
+
+		w := gobitstream.NewWriterLE(80)
+		a.Nil(w.WriteUvarint(val))
+		a.Nil(w.Flush())
+		a.Equal(expected, w.Bytes())
+
+		rd, err := gobitstream.NewReaderLE(len(expected)*8, expected)
+		a.Nil(err)
+		v, err := rd.ReadUvarint()
+		a.Nil(err)
+		a.Equal(val, v)
+		a.Equal(0, rd.Remaining())
+	}
+
+	for _, val := range []int64{0, -1, 1, -64, 64, math.MinInt64, math.MaxInt64} {
+		expected := binary.AppendVarint(nil, val)
+
+		w := gobitstream.NewWriterLE(80)
+		a.Nil(w.WriteVarint(val))
+		a.Nil(w.Flush())
+		a.Equal(expected, w.Bytes())
+
+		rd, err := gobitstream.NewReaderLE(len(expected)*8, expected)
+		a.Nil(err)
+		v, err := rd.ReadVarint()
+		a.Nil(err)
+		a.Equal(val, v)
+	}
+}
+
+func TestVarintUnaligned(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	for _, groupBits := range []int{1, 3, 7, 15, 63} {
+		w := gobitstream.NewGrowableWriterLE()
+		values := make([]uint64, 100)
+		signed := make([]int64, 100)
+		for i := range values {
+			values[i] = r.Uint64() >> uint(r.Intn(64))
+			signed[i] = int64(r.Uint64()) >> uint(r.Intn(64))
+			a.Nil(w.WriteNbitsFromWord(3, 0x5))
+			a.Nil(w.WriteUvarintGroup(groupBits, values[i]))
+			a.Nil(w.WriteVarintGroup(groupBits, signed[i]))
+		}
+		a.Nil(w.Flush())
+
+		rd, err := gobitstream.NewReaderLE(len(w.Bytes())*8, w.Bytes())
+		a.Nil(err)
+		for i := range values {
+			marker, err := rd.ReadNbitsUint64(3)
+			a.Nil(err)
+			a.Equal(uint64(0x5), marker)
+
+			v, err := rd.ReadUvarintGroup(groupBits)
+			a.Nil(err)
+			a.Equal(values[i], v, "group bits: %d", groupBits)
+
+			s, err := rd.ReadVarintGroup(groupBits)
+			a.Nil(err)
+			a.Equal(signed[i], s, "group bits: %d", groupBits)
+		}
+	}
+}
+
+func TestVarintErrors(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	// Eleven bytes with the continuation bit set overflow 64 bits.
+	in := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}
+	rd, err := gobitstream.NewReaderLE(len(in)*8, in)
+	a.Nil(err)
+	_, err = rd.ReadUvarint()
+	a.Equal(gobitstream.CodeOverflowError, errors.Cause(err))
+	a.Equal(0, rd.Offset())
+
+	// The tenth byte can only carry one bit.
+	in = []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02}
+	rd, err = gobitstream.NewReaderLE(len(in)*8, in)
+	a.Nil(err)
+	_, err = rd.ReadUvarint()
+	a.Equal(gobitstream.CodeOverflowError, errors.Cause(err))
+
+	in = []byte{0xFF, 0xFF}
+	rd, err = gobitstream.NewReaderLE(len(in)*8, in)
+	a.Nil(err)
+	_, err = rd.ReadUvarint()
+	a.Equal(gobitstream.TruncatedCodeError, errors.Cause(err))
+	a.Equal(0, rd.Offset())
+
+	_, err = rd.ReadUvarintGroup(0)
+	a.NotNil(err)
+	a.NotNil(gobitstream.NewWriterLE(8).WriteUvarintGroup(64, 0))
+}
@@ -2,11 +2,12 @@ package gobitstream
 
 import (
 	"encoding/binary"
+
 	"github.com/pkg/errors"
 )
 
 // Writer is a bit stream writer.
-// It does not have io.Writer interface
+// It implements io.Writer, io.ByteWriter and io.ReaderFrom, writing bytes at the current bit offset.
 type Writer struct {
 	dst            []byte
 	dstWord        []uint64
@@ -18,6 +19,17 @@ type Writer struct {
 	sizeInBytes    int
 	sizeInWords    int
 	isLittleEndian bool
+	growable       bool         // dstWord grows on demand instead of having a fixed size
+	bitOrder       BitOrder     // Order of the bits within each byte and field
+	scratchBytes   []byte       // Reused by WriteNbitsFromBytes to reorder its input
+	scratchWords   []uint64     // Reused by WriteNbitsFromBytes to pack its input into words
+	strict         bool         // Values wider than their field are rejected instead of masked
+	inPlace        bool         // Fields are written to the caller's bytes in on, dstWord only caches them for reading
+	on             []byte       // Caller's bytes written in place, see NewWriterOn
+	onOffset       int          // Offset in bits of the bit stream within on
+	coverage       CoverageMode // Whether the bits written are tracked in written and overlapped
+	written        []uint64     // Bitmap of the bits written, when coverage is tracked
+	overlapped     []uint64     // Bitmap of the bits written more than once, when coverage is tracked
 }
 
 func newWriter(totalBits int) *Writer {
@@ -42,30 +54,107 @@ func NewWriterBE(totalBits int) *Writer {
 	return wr
 }
 
+// NewGrowableWriterLE creates a new little-endian Writer with no fixed total size.
+// Its backing words grow automatically as fields are written.
+func NewGrowableWriterLE() *Writer {
+	wr := newWriter(0)
+	wr.isLittleEndian = true
+	wr.growable = true
+	return wr
+}
+
+// NewGrowableWriterBE creates a new big-endian Writer with no fixed total size.
+// Its backing words grow automatically as fields are written.
+func NewGrowableWriterBE() *Writer {
+	wr := newWriter(0)
+	wr.isLittleEndian = false
+	wr.growable = true
+	return wr
+}
+
+// grow makes room for nBits more bits after the current offset when the Writer is growable.
+// The capacity is doubled on reallocation so appending is amortized constant time.
+func (wr *Writer) grow(nBits int) {
+	if !wr.growable {
+		return
+	}
+	need := bitsToWordSize(wr.offset + nBits)
+	if need <= len(wr.dstWord) {
+		return
+	}
+	if need <= cap(wr.dstWord) {
+		wr.dstWord = wr.dstWord[:need]
+		return
+	}
+	words := make([]uint64, need, 2*cap(wr.dstWord)+need)
+	copy(words, wr.dstWord)
+	wr.dstWord = words
+}
+
+// Flush converts the bits written so far to bytes, available from Bytes.
+// The byte slice is reused by the next Flush, use AppendBytes to keep the bytes of several flushes.
+// A Writer created with NewWriterOn has already written its bits to the caller's bytes, Flush only copies them.
+// In CoverageFail mode a *CoverageError is returned, after the bytes are converted, if bits were never written
+// or were written more than once.
 func (wr *Writer) Flush() (err error) {
+	wr.loadWords()
 	sizeInBytes := len(wr.dstWord) * 8
-	wr.dst = make([]byte, sizeInBytes)
-	sizeInBytes = BitsToBytesSize(wr.offset)
-	wr.dst, err = convertWordsToBytes(wr.dstWord, wr.dst, wr.offset, wr.isLittleEndian)
+	if cap(wr.dst) < sizeInBytes {
+		wr.dst = make([]byte, sizeInBytes)
+	}
+	wr.dst, err = convertWordsToBytes(wr.dstWord, wr.dst[:sizeInBytes], wr.offset, wr.isLittleEndian)
 	if err != nil {
-		err = errors.WithStack(err)
+		return errors.WithStack(err)
+	}
+	if wr.bitOrder == MSBFirst {
+		reverseBitsInBytes(wr.dst)
+	}
+	return wr.checkCoverage()
+}
+
+// AppendBytes appends the bytes Flush would produce to dst and returns the extended slice.
+// It does not allocate when dst has room for the written words, and leaves the slice returned by Bytes untouched.
+func (wr *Writer) AppendBytes(dst []byte) []byte {
+	wr.loadWords()
+	start := len(dst)
+	for _, word := range wr.dstWord[:bitsToWordSize(wr.offset)] {
+		dst = binary.LittleEndian.AppendUint64(dst, word)
+	}
+	dst = dst[:start+BitsToBytesSize(wr.offset)]
+	if !wr.isLittleEndian {
+		reverseSlice(dst[start:])
 	}
-	return err
+	if wr.bitOrder == MSBFirst {
+		reverseBitsInBytes(dst[start:])
+	}
+	return dst
+}
+
+// Reset discards the bits written so far so the Writer can be reused, keeping its backing memory.
+// The orders, the size, the growable setting and the coverage mode are kept.
+// A Writer created with NewWriterOn leaves the caller's bytes as they are until they are written again.
+func (wr *Writer) Reset() {
+	for i := range wr.dstWord {
+		wr.dstWord[i] = 0
+	}
+	if wr.growable {
+		wr.dstWord = wr.dstWord[:0]
+	}
+	wr.dst = wr.dst[:0]
+	wr.written = wr.written[:0]
+	wr.overlapped = wr.overlapped[:0]
+	wr.offset = 0
 }
 
 func convertWordsToBytes(words []uint64, outBuffer []byte, sizeInBits int, isLittleEndian bool) ([]byte, error) {
 	sizeInBytes := BitsToBytesSize(sizeInBits)
-	//if isLittleEndian {
 	for i, word := range words {
 		binary.LittleEndian.PutUint64(outBuffer[i*8:i*8+8], word)
 	}
 
 	outBuffer = outBuffer[0:sizeInBytes]
 	if !isLittleEndian {
-		outBuffer2 := make([]byte, len(outBuffer))
-		_ = copy(outBuffer2, outBuffer)
-		reverseSlice(outBuffer2)
-		return outBuffer2, nil
+		reverseSlice(outBuffer)
 	}
 	return outBuffer, nil
 }
@@ -74,15 +163,26 @@ func convertWordsToBytes(words []uint64, outBuffer []byte, sizeInBits int, isLit
 // The nBits parameter determines the number of bits to write.
 // The xval byte slice contains the input bytes to be written.
 // If the writer's endianness is not little endian, the byte order is reversed before writing.
+// If the writer's bit order is MSB first, the bits are taken starting at the most significant bit of the first byte.
 // The function returns an error if the byte size is invalid or if there was an error during the field assignment.
+// In strict mode a *FieldOverflowError is returned, and nothing is written, if a bit of xval past nBits is set.
 func (wr *Writer) WriteNbitsFromBytes(nBits int, xval []byte) error {
 	var val []byte
 
-	// Reverse byte order if the writer's endianness is not little endian
-	if !wr.isLittleEndian {
-		val = make([]byte, len(xval))
+	// Reverse byte order if the writer's endianness is not little endian,
+	// and the bits of each byte if the writer's bit order is MSB first.
+	if !wr.isLittleEndian || wr.bitOrder == MSBFirst {
+		if cap(wr.scratchBytes) < len(xval) {
+			wr.scratchBytes = make([]byte, len(xval))
+		}
+		val = wr.scratchBytes[:len(xval)]
 		_ = copy(val, xval)
-		reverseSlice(val)
+		if !wr.isLittleEndian {
+			reverseSlice(val)
+		}
+		if wr.bitOrder == MSBFirst {
+			reverseBitsInBytes(val)
+		}
 	} else {
 		val = xval
 	}
@@ -92,14 +192,26 @@ func (wr *Writer) WriteNbitsFromBytes(nBits int, xval []byte) error {
 		return errors.WithStack(err)
 	}
 
-	words, errConv := ConvertBytesToWords(nBits, val)
-	if errConv != nil {
-		return errors.WithStack(errConv)
+	// In strict mode all the input bits are packed, to check that the ones past nBits are zero.
+	packedBits := nBits
+	if wr.strict {
+		packedBits = len(val) * 8
+	}
+	wordSize := bitsToWordSize(packedBits)
+	if cap(wr.scratchWords) < wordSize {
+		wr.scratchWords = make([]uint64, wordSize)
 	}
+	words := wr.scratchWords[:wordSize]
+	packBytesToWords(words, packedBits, val)
+	if err := wr.checkStrictWords(nBits, words); err != nil {
+		return err
+	}
+	words = words[:bitsToWordSize(nBits)]
+
+	wr.grow(nBits)
 
-	var errSet error
-	if wr.dstWord, errSet = SetFieldToSlice(wr.dstWord, words, uint64(nBits), uint64(wr.offset)); errSet != nil {
-		return errors.WithStack(errSet)
+	if err := wr.setFieldWords(wr.offset, nBits, words); err != nil {
+		return err
 	}
 
 	wr.offset += nBits
@@ -112,27 +224,102 @@ func (wr *Writer) WriteNbitsFromBytes(nBits int, xval []byte) error {
 // If the number of bits exceeds 64, the function returns an error.
 // The function performs a field assignment based on the current writer's offset.
 // It returns an error if there was an error during the field assignment.
+// In strict mode a *FieldOverflowError is returned, and nothing is written, if val does not fit in nBits bits.
 func (wr *Writer) WriteNbitsFromWord(nBits int, val uint64) error {
 	if nBits > 64 {
 		return errors.New("invalid number of bits: exceeds 64")
 	}
+	if err := wr.checkStrict(nBits, val); err != nil {
+		return err
+	}
+	return wr.writeNbits(nBits, val)
+}
+
+// writeNbits writes the nBits low bits of val, whatever the strict mode. It is used by the encoders
+// whose values are built with bits above the field width.
+func (wr *Writer) writeNbits(nBits int, val uint64) error {
+	if nBits > 64 {
+		return errors.New("invalid number of bits: exceeds 64")
+	}
 
-	var err error
+	wr.grow(nBits)
 
-	if wr.offset >= 64 {
-		wr.dstWord, err = SetFieldToSlice(wr.dstWord, []uint64{val}, uint64(nBits), uint64(wr.offset))
-	} else {
-		wr.dstWord, err = Set64BitsFieldToSlice(wr.dstWord, val, uint64(nBits), uint64(wr.offset))
+	if wr.bitOrder == MSBFirst {
+		val = reverseField(val, nBits)
 	}
-	if err != nil {
-		return errors.WithStack(err)
+
+	if err := wr.setField(wr.offset, nBits, val); err != nil {
+		return err
 	}
 
 	wr.offset += nBits
 	return nil
 }
 
+// writeRun writes nBits copies of bit, 64 bits at a time.
+func (wr *Writer) writeRun(bit uint64, nBits int) error {
+	var pattern uint64
+	if bit != 0 {
+		pattern = ^uint64(0)
+	}
+	for nBits > 0 {
+		chunk := nBits
+		if chunk > 64 {
+			chunk = 64
+		}
+		if err := wr.writeNbits(chunk, pattern); err != nil {
+			return err
+		}
+		nBits -= chunk
+	}
+	return nil
+}
+
+// writeNbitsFromWords writes the nBits low bits of words, least significant word first, as a single field.
+// With MSBFirst bit order the most significant bit of the field is written first, as ReadNbitsWords64 reads it.
+// On error the offset is restored to where the field starts. In strict mode the words past nBits must be zero.
+func (wr *Writer) writeNbitsFromWords(nBits int, words []uint64) error {
+	if nBits <= 0 || len(words) < sizeInWords(nBits) {
+		return errors.Wrapf(InvalidBitsSizeError, "nBits: %d, words: %d", nBits, len(words))
+	}
+	if err := wr.checkStrictWords(nBits, words); err != nil {
+		return err
+	}
+	n := sizeInWords(nBits)
+	start := wr.offset
+	for i := 0; i < n; i++ {
+		index := i
+		if wr.bitOrder == MSBFirst {
+			index = n - 1 - i
+		}
+		width := 64
+		if index == n-1 {
+			width = nBits - 64*(n-1)
+		}
+		if err := wr.writeNbits(width, words[index]); err != nil {
+			wr.offset = start
+			return err
+		}
+	}
+	return nil
+}
+
+// Reader returns a Reader over the bits written so far, sharing the backing words of the Writer.
+// No Flush is needed. Writes done after this call past the current offset are not visible to the Reader,
+// and a growable Writer may stop sharing its words with the Reader once it reallocates them.
+// For a Writer created with NewWriterOn the Reader reads a copy of the bits written so far.
+func (wr *Writer) Reader() *Reader {
+	wr.loadWords()
+	return &Reader{
+		size:           wr.offset,
+		inWord:         wr.dstWord,
+		isLittleEndian: wr.isLittleEndian,
+		bitOrder:       wr.bitOrder,
+	}
+}
+
 func (wr *Writer) CurrentWord() []uint64 {
+	wr.loadWords()
 	return wr.dstWord
 }
 
@@ -140,10 +327,21 @@ func (wr *Writer) Bytes() []byte {
 	return wr.dst
 }
 
+// Words returns the backing words of the Writer.
+// For a growable Writer, or one created with NewWriterOn, only the words holding bits actually written are returned.
 func (wr *Writer) Words() []uint64 {
+	wr.loadWords()
+	if wr.growable || wr.inPlace {
+		return wr.dstWord[:bitsToWordSize(wr.offset)]
+	}
 	return wr.dstWord
 }
 
+// Uint64 returns the first backing word of the Writer, or 0 when it has none, as a growable Writer before any write.
 func (wr *Writer) Uint64() uint64 {
-	return wr.CurrentWord()[0]
+	words := wr.CurrentWord()
+	if len(words) == 0 {
+		return 0
+	}
+	return words[0]
 }
@@ -0,0 +1,83 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+func TestGrowableWriterMatchesWriter(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	for _, le := range []bool{true, false} {
+		const totalBits = 3000
+		var w, g *gobitstream.Writer
+		if le {
+			w, g = gobitstream.NewWriterLE(totalBits), gobitstream.NewGrowableWriterLE()
+		} else {
+			w, g = gobitstream.NewWriterBE(totalBits), gobitstream.NewGrowableWriterBE()
+		}
+
+		for offset := 0; offset < totalBits; {
+			width := r.Intn(150) + 1
+			if width > totalBits-offset {
+				width = totalBits - offset
+			}
+			if width <= 64 {
+				val := r.Uint64()
+				a.Nil(w.WriteNbitsFromWord(width, val))
+				a.Nil(g.WriteNbitsFromWord(width, val))
+			} else {
+				val := make([]byte, gobitstream.BitsToBytesSize(width))
+				r.Read(val)
+				a.Nil(w.WriteNbitsFromBytes(width, val))
+				a.Nil(g.WriteNbitsFromBytes(width, val))
+			}
+			offset += width
+			a.Len(g.Words(), (offset+63)/64)
+		}
+
+		a.Nil(w.Flush())
+		a.Nil(g.Flush())
+		a.Equal(w.Words(), g.Words())
+		a.Equal(w.Bytes(), g.Bytes())
+	}
+}
+
+func TestGrowableWriterOnlyWrittenBits(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	g := gobitstream.NewGrowableWriterLE()
+	a.Len(g.Words(), 0)
+
+	a.Nil(g.WriteNbitsFromWord(12, 0xABC))
+	a.Nil(g.Flush())
+	a.Equal([]uint64{0xABC}, g.Words())
+	a.Equal([]byte{0xBC, 0x0A}, g.Bytes())
+
+	a.Nil(g.WriteNbitsFromWord(64, 0xFFFFFFFFFFFFFFFF))
+	a.Nil(g.Flush())
+	a.Equal([]uint64{0xFFFFFFFFFFFFFABC, 0xFFF}, g.Words())
+	a.Len(g.Bytes(), 10)
+}
+
+func TestFixedWriterDoesNotGrow(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewWriterLE(8)
+	a.Nil(w.WriteNbitsFromWord(8, 0xFF))
+	a.NotNil(w.WriteNbitsFromWord(64, 0xFF))
+}
+
+func TestGrowableWriterUint64WithoutWords(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	for _, g := range []*gobitstream.Writer{gobitstream.NewGrowableWriterLE(), gobitstream.NewGrowableWriterBE()} {
+		a.Equal(uint64(0), g.Uint64())
+		a.Nil(g.WriteNbitsFromWord(12, 0xABC))
+		a.Equal(uint64(0xABC), g.Uint64())
+		g.Reset()
+		a.Equal(uint64(0), g.Uint64())
+	}
+}
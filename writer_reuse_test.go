@@ -0,0 +1,132 @@
+package gobitstream_test
+
+import (
+	"testing"
+
+	"github.com/lagarciag/gobitstream"
+	"github.com/lagarciag/gobitstream/tests"
+)
+
+// writePacket writes a small packet mixing word and byte fields, as a packet generator would.
+func writePacket(w *gobitstream.Writer, seq uint64, payload []byte) error {
+	if err := w.WriteNbitsFromWord(4, 0x4); err != nil {
+		return err
+	}
+	if err := w.WriteNbitsFromWord(12, seq); err != nil {
+		return err
+	}
+	if err := w.WriteNbitsFromBytes(len(payload)*8, payload); err != nil {
+		return err
+	}
+	return w.WriteNbitsFromWord(13, seq^0x1FFF)
+}
+
+func TestWriterResetAndAppendBytes(t *testing.T) {
+	_, a, r := tests.InitTest(t)
+
+	payload := make([]byte, 21)
+	r.Read(payload)
+
+	for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+		for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+			reused, err := gobitstream.NewWriterWithOrder(1024, byteOrder, bitOrder)
+			a.Nil(err)
+			var appended []byte
+			for seq := uint64(0); seq < 5; seq++ {
+				fresh, err := gobitstream.NewWriterWithOrder(1024, byteOrder, bitOrder)
+				a.Nil(err)
+				a.Nil(writePacket(fresh, seq, payload[seq:]))
+				a.Nil(fresh.Flush())
+
+				reused.Reset()
+				a.Nil(writePacket(reused, seq, payload[seq:]))
+				a.Nil(reused.Flush())
+				a.Equal(fresh.Bytes(), reused.Bytes())
+				a.Equal(fresh.Words(), reused.Words())
+
+				start := len(appended)
+				appended = reused.AppendBytes(appended)
+				a.Equal(fresh.Bytes(), appended[start:])
+			}
+		}
+	}
+}
+
+func TestGrowableWriterReset(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	w := gobitstream.NewGrowableWriterBE()
+	a.Nil(w.WriteNbitsFromWord(64, 0xFFFFFFFFFFFFFFFF))
+	a.Nil(w.WriteNbitsFromWord(64, 0xFFFFFFFFFFFFFFFF))
+	w.Reset()
+	a.Equal(0, len(w.Words()))
+	a.Equal(0, len(w.Bytes()))
+	a.Equal(0, w.Reader().Remaining())
+
+	a.Nil(w.WriteNbitsFromWord(12, 0x123))
+	a.Equal([]uint64{0x123}, w.Words())
+	a.Nil(w.Flush())
+	a.Equal([]byte{0x01, 0x23}, w.Bytes())
+	a.Equal([]byte{0xAA, 0x01, 0x23}, w.AppendBytes([]byte{0xAA}))
+}
+
+func TestWriterSteadyStateDoesNotAllocate(t *testing.T) {
+	_, a, _ := tests.InitTest(t)
+
+	payload := make([]byte, 64)
+	for _, byteOrder := range []gobitstream.ByteOrder{gobitstream.LittleEndian, gobitstream.BigEndian} {
+		for _, bitOrder := range []gobitstream.BitOrder{gobitstream.LSBFirst, gobitstream.MSBFirst} {
+			w, err := gobitstream.NewWriterWithOrder(1024, byteOrder, bitOrder)
+			a.Nil(err)
+			growable := gobitstream.NewGrowableWriterLE()
+			out := make([]byte, 0, 256)
+
+			seq := uint64(0)
+			allocs := testing.AllocsPerRun(100, func() {
+				seq++
+				w.Reset()
+				_ = writePacket(w, seq, payload)
+				_ = w.Flush()
+				out = w.AppendBytes(out[:0])
+
+				growable.Reset()
+				_ = writePacket(growable, seq, payload)
+				out = growable.AppendBytes(out[:0])
+			})
+			a.Equal(float64(0), allocs)
+		}
+	}
+}
+
+func BenchmarkWriterNewPerPacket(b *testing.B) {
+	payload := make([]byte, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w := gobitstream.NewWriterBE(1024)
+		_ = writePacket(w, uint64(i), payload)
+		_ = w.Flush()
+	}
+}
+
+func BenchmarkWriterReset(b *testing.B) {
+	payload := make([]byte, 64)
+	w := gobitstream.NewWriterBE(1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+		_ = writePacket(w, uint64(i), payload)
+		_ = w.Flush()
+	}
+}
+
+func BenchmarkWriterAppendBytes(b *testing.B) {
+	payload := make([]byte, 64)
+	w := gobitstream.NewWriterBE(1024)
+	out := make([]byte, 0, 128)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+		_ = writePacket(w, uint64(i), payload)
+		out = w.AppendBytes(out[:0])
+	}
+}